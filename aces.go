@@ -0,0 +1,32 @@
+package color
+
+// ACEScg is a color in the ACEScg working space used in VFX and film pipelines,
+// with the AP1 primaries and D60 white point. Its channels are scene linear,
+// with no display transfer function applied, and may exceed 1 for bright light.
+type ACEScg struct {
+	R, G, B float64
+}
+
+// ToACEScg linearizes c and moves it from the sRGB primaries to AP1
+func (c RGB) ToACEScg() ACEScg {
+	r, g, b := Linearize(c.R), Linearize(c.G), Linearize(c.B)
+	return ACEScg{
+		0.6130974024*r + 0.3395231462*g + 0.0473794514*b,
+		0.0701937225*r + 0.9163538791*g + 0.0134523985*b,
+		0.0206155929*r + 0.1095697729*g + 0.8698146342*b,
+	}
+}
+
+// ToRGB moves c from the AP1 primaries to sRGB and applies the sRGB transfer function.
+// Colors outside the sRGB gamut come back with channels outside [0, 1].
+func (c ACEScg) ToRGB() RGB {
+	return RGB{
+		Delinearize(1.7050509927*c.R - 0.6217921207*c.G - 0.0832588720*c.B),
+		Delinearize(-0.1302564176*c.R + 1.1408047365*c.G - 0.0105483191*c.B),
+		Delinearize(-0.0240033568*c.R - 0.1289689760*c.G + 1.1529723328*c.B),
+	}
+}
+
+func (c ACEScg) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
@@ -0,0 +1,30 @@
+package color
+
+import (
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var _ color.Color = ACEScg{}
+
+func TestRGBtoACEScgtoRGB(t *testing.T) {
+	const epsilon = 1e-8
+	for range nTrials {
+		want := RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		t.Run(want.ToHTML(), func(t *testing.T) {
+			have := want.ToACEScg().ToRGB()
+			if real.Diff(have.R, want.R) > epsilon || real.Diff(have.G, want.G) > epsilon || real.Diff(have.B, want.B) > epsilon {
+				t.Errorf("have %v, want %v", have, want)
+			}
+		})
+	}
+
+	// the white points are adapted onto each other, so white stays white
+	w := white.ToACEScg()
+	if real.Diff(w.R, 1) > epsilon || real.Diff(w.G, 1) > epsilon || real.Diff(w.B, 1) > epsilon {
+		t.Errorf("white: have %v, want {1 1 1}", w)
+	}
+}
@@ -0,0 +1,61 @@
+package color
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Adobe Color Table files hold 256 RGB triples, optionally followed by a
+// big endian count of the colors in use and the index of the transparent one
+const (
+	actColors  = 256
+	actSize    = 3 * actColors
+	actExtSize = actSize + 4
+	actNoAlpha = 0xffff
+)
+
+// LoadACT reads an Adobe Color Table (.act) palette.
+// Files of the 772 byte layout yield as many colors as their count field gives,
+// plain 768 byte files yield all 256.
+func LoadACT(r io.Reader) (Palette, error) {
+	buf, err := io.ReadAll(io.LimitReader(r, actExtSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	n := actColors
+	switch len(buf) {
+	case actSize:
+	case actExtSize:
+		if count := int(binary.BigEndian.Uint16(buf[actSize:])); count > 0 && count <= actColors {
+			n = count
+		}
+	default:
+		return nil, fmt.Errorf("act: invalid file size %d, want %d or %d", len(buf), actSize, actExtSize)
+	}
+
+	p := make(Palette, n)
+	for i := range p {
+		p[i] = RGB{float64(buf[3*i]) / 0xff, float64(buf[3*i+1]) / 0xff, float64(buf[3*i+2]) / 0xff}
+	}
+	return p, nil
+}
+
+// WriteACT writes p as an Adobe Color Table in the 772 byte layout,
+// padding it to 256 entries with black and recording its length in the count field.
+// It has no transparent index.
+func (p Palette) WriteACT(w io.Writer) error {
+	if len(p) > actColors {
+		return errors.New("act: palettes hold at most 256 colors")
+	}
+	buf := make([]byte, actExtSize)
+	for i, c := range p {
+		buf[3*i], buf[3*i+1], buf[3*i+2] = channel8(c.R), channel8(c.G), channel8(c.B)
+	}
+	binary.BigEndian.PutUint16(buf[actSize:], uint16(len(p)))
+	binary.BigEndian.PutUint16(buf[actSize+2:], actNoAlpha)
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,48 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestACT(t *testing.T) {
+	want := Palette{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}}
+
+	var buf bytes.Buffer
+	if err := want.WriteACT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != actExtSize {
+		t.Errorf("have %d bytes, want %d", buf.Len(), actExtSize)
+	}
+	raw := bytes.Clone(buf.Bytes())
+
+	have, err := LoadACT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d", len(have), len(want))
+	}
+	for i := range want {
+		if !eqRGB(have[i], want[i]) {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+
+	// without the trailing count every one of the 256 slots comes back, padded with black
+	padded, err := LoadACT(bytes.NewReader(raw[:actSize]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(padded) != actColors || padded[3] != have[3] || padded[actColors-1] != black {
+		t.Errorf("have %d colors ending %v, want %d ending in black", len(padded), padded[len(padded)-1], actColors)
+	}
+
+	if _, err := LoadACT(bytes.NewReader(raw[:100])); err == nil {
+		t.Errorf("truncated file: have no error")
+	}
+	if err := make(Palette, 257).WriteACT(&buf); err == nil {
+		t.Errorf("257 colors: have no error")
+	}
+}
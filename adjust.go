@@ -0,0 +1,234 @@
+package color
+
+import "math"
+
+// clamp01 saturates v into [0, 1]
+func clamp01(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+// Clamp saturates each channel of c into [0, 1], making a valid color of the
+// results of arithmetic that may overshoot
+func (c RGB) Clamp() RGB {
+	return RGB{clamp01(c.R), clamp01(c.G), clamp01(c.B)}
+}
+
+// Clamp saturates each component of c into [0, 1]
+func (c HSL) Clamp() HSL {
+	return HSL{clamp01(c.H), clamp01(c.S), clamp01(c.L)}
+}
+
+// Valid reports whether every channel of c lies in [0, 1], the range the
+// conversions are defined over. NaNs are invalid.
+func (c RGB) Valid() bool {
+	return c.R >= 0 && c.R <= 1 && c.G >= 0 && c.G <= 1 && c.B >= 0 && c.B <= 1
+}
+
+// ApplyMatrix multiplies the column vector (R, G, B) by m and clamps the result into [0, 1]
+func (c RGB) ApplyMatrix(m [3][3]float64) RGB {
+	return RGB{
+		clamp01(m[0][0]*c.R + m[0][1]*c.G + m[0][2]*c.B),
+		clamp01(m[1][0]*c.R + m[1][1]*c.G + m[1][2]*c.B),
+		clamp01(m[2][0]*c.R + m[2][1]*c.G + m[2][2]*c.B),
+	}
+}
+
+// Sepia returns the classic brownish tone of c
+func (c RGB) Sepia() RGB {
+	return RGB{
+		clamp01(0.393*c.R + 0.769*c.G + 0.189*c.B),
+		clamp01(0.349*c.R + 0.686*c.G + 0.168*c.B),
+		clamp01(0.272*c.R + 0.534*c.G + 0.131*c.B),
+	}
+}
+
+// Invert returns the photographic negative of c, {1-R, 1-G, 1-B}.
+// Unlike Complement it flips lightness too, so dark colors become light and
+// grays other than the middle one become a different gray.
+func (c RGB) Invert() RGB {
+	return RGB{1 - c.R, 1 - c.G, 1 - c.B}
+}
+
+// Complement returns the color opposite c on the color wheel,
+// with its hue rotated by half a turn. Saturation and lightness are kept,
+// so unlike RGB.Invert grays are their own complements.
+func (c HSL) Complement() HSL {
+	c.H = wrapHue(c.H + 0.5)
+	return c
+}
+
+// Complement returns the color opposite c on the color wheel, see HSL.Complement
+func (c RGB) Complement() RGB {
+	return c.ToHSL().Complement().ToRGB()
+}
+
+// AccentComplement returns the complement of c with its saturation raised by
+// satBoost and its lightness shifted by lightShift, both clamped into [0, 1],
+// to give an accent that stands out against c
+func (c RGB) AccentComplement(satBoost, lightShift float64) RGB {
+	return c.ToHSL().Complement().Adjust(0, satBoost, lightShift).ToRGB()
+}
+
+// Adjust rotates the hue of c by dh turns, wrapping around the wheel, and
+// shifts its saturation and lightness by ds and dl, clamping them into [0, 1]
+func (c HSL) Adjust(dh, ds, dl float64) HSL {
+	return HSL{wrapHue(c.H + dh), clamp01(c.S + ds), clamp01(c.L + dl)}
+}
+
+// Adjust shifts the hue, saturation, and lightness of c, see HSL.Adjust
+func (c RGB) Adjust(dh, ds, dl float64) RGB {
+	return c.ToHSL().Adjust(dh, ds, dl).ToRGB()
+}
+
+// Lighten raises the lightness of c by amount, clamping it at 1
+func (c HSL) Lighten(amount float64) HSL {
+	return c.Adjust(0, 0, amount)
+}
+
+// Darken lowers the lightness of c by amount, clamping it at 0
+func (c HSL) Darken(amount float64) HSL {
+	return c.Adjust(0, 0, -amount)
+}
+
+// Lighten raises the HSL lightness of c by amount, see HSL.Lighten
+func (c RGB) Lighten(amount float64) RGB {
+	return c.ToHSL().Lighten(amount).ToRGB()
+}
+
+// Darken lowers the HSL lightness of c by amount, see HSL.Darken
+func (c RGB) Darken(amount float64) RGB {
+	return c.ToHSL().Darken(amount).ToRGB()
+}
+
+// Saturate raises the saturation of c by amount, clamping it at 1
+func (c HSL) Saturate(amount float64) HSL {
+	return c.Adjust(0, amount, 0)
+}
+
+// Desaturate lowers the saturation of c by amount, clamping it at 0
+func (c HSL) Desaturate(amount float64) HSL {
+	return c.Adjust(0, -amount, 0)
+}
+
+// Grayscale returns the gray with the same relative luminance as c, weighting
+// the linearized channels by the Rec. 709 coefficients as Luminance does,
+// rather than averaging them
+func (c RGB) Grayscale() RGB {
+	v := Delinearize(c.Luminance())
+	return RGB{v, v, v}
+}
+
+// The saturation and lightness of pastel colors
+const (
+	pastelS = 0.4
+	pastelL = 0.8
+)
+
+// Pastel returns a soft, light version of c with the same hue.
+// Grays stay gray, only their lightness is raised.
+func (c RGB) Pastel() RGB {
+	h, ok := c.StableHue()
+	if !ok {
+		return HSL{0, 0, pastelL}.ToRGB()
+	}
+	return HSL{h, pastelS, pastelL}.ToRGB()
+}
+
+// How far, in CIELAB L*, a border sits from its fill
+const borderShift = 15
+
+// BorderColor returns a border for a fill of c: a shade darker for light fills
+// and a shade lighter for dark ones, the same hue either way. Vivid fills
+// whose shade falls outside sRGB lose chroma rather than lightness.
+func (c RGB) BorderColor() RGB {
+	lab := c.ToLab()
+	if lab.L > 50 {
+		lab.L -= borderShift
+	} else {
+		lab.L += borderShift
+	}
+	return lab.fitGamut()
+}
+
+// ClampAll returns a copy of colors with every channel saturated into [0, 1]
+func ClampAll(colors []RGB) []RGB {
+	out := make([]RGB, len(colors))
+	for i, c := range colors {
+		out[i] = c.Clamp()
+	}
+	return out
+}
+
+// NormalizeAll returns a copy of colors scaled by a common factor so that the
+// largest channel across the whole set becomes 1. Relative intensities are kept.
+// A set with no positive channel is returned unscaled.
+func NormalizeAll(colors []RGB) []RGB {
+	var peak float64
+	for _, c := range colors {
+		peak = max(peak, c.R, c.G, c.B)
+	}
+	out := make([]RGB, len(colors))
+	for i, c := range colors {
+		if peak > 0 {
+			c = RGB{c.R / peak, c.G / peak, c.B / peak}
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// The widest and narrowest gaps in CIELAB L* between neighboring lightness steps
+const (
+	lightnessStep    = 10
+	minLightnessStep = 1
+)
+
+// LightnessSteps returns n shades of c whose CIELAB L* are evenly spaced and
+// centered on that of c, running from dark to light, as for the hover, active,
+// and disabled states of a control. The gap between shades is at most 10 L*,
+// narrowing as needed to keep all of them within black and white.
+// When c is so near black or white that the gap would drop under 1 L*, the
+// shades run from c toward the other end instead, so c comes first or last.
+// Shades that fall outside the sRGB gamut are desaturated to fit.
+func (c RGB) LightnessSteps(n int) []RGB {
+	if n <= 0 {
+		return nil
+	}
+	lab := c.ToLab()
+	half := float64(n-1) / 2
+	step, first := float64(lightnessStep), -half
+	if half > 0 {
+		step = min(step, lab.L/half, (100-lab.L)/half)
+		if step < minLightnessStep {
+			if lab.L < 50 {
+				step, first = min(lightnessStep, (100-lab.L)/(2*half)), 0
+			} else {
+				step, first = min(lightnessStep, lab.L/(2*half)), -2*half
+			}
+		}
+	}
+
+	out := make([]RGB, n)
+	for i := range out {
+		offset := first + float64(i)
+		if offset == 0 {
+			out[i] = c
+			continue
+		}
+		shade := lab
+		shade.L += offset * step
+		out[i] = shade.fitGamut()
+	}
+	return out
+}
+
+// QuantizeBits snaps each channel of c to the nearest of the 2^bits evenly
+// spaced levels a channel of that many bits can hold, for mimicking low bit
+// depth displays. bits is clamped into [1, 8].
+func (c RGB) QuantizeBits(bits int) RGB {
+	levels := float64(int(1)<<min(max(bits, 1), 8) - 1)
+	q := func(v float64) float64 {
+		return math.Round(clamp01(v)*levels) / levels
+	}
+	return RGB{q(c.R), q(c.G), q(c.B)}
+}
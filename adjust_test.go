@@ -0,0 +1,342 @@
+package color
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+// eqRGB reports whether each channel of l and r is within epsilonF of the other
+func eqRGB(l, r RGB) bool {
+	return real.Diff(l.R, r.R) <= epsilonF && real.Diff(l.G, r.G) <= epsilonF && real.Diff(l.B, r.B) <= epsilonF
+}
+
+func TestApplyMatrix(t *testing.T) {
+	identity := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	sepia := [3][3]float64{
+		{0.393, 0.769, 0.189},
+		{0.349, 0.686, 0.168},
+		{0.272, 0.534, 0.131},
+	}
+	for range nTrials {
+		c := RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			if have := c.ApplyMatrix(identity); !eqRGB(have, c) {
+				t.Errorf("identity: have %v, want %v", have, c)
+			}
+			if have, want := c.ApplyMatrix(sepia), c.Sepia(); !eqRGB(have, want) {
+				t.Errorf("sepia: have %v, want %v", have, want)
+			}
+		})
+	}
+}
+
+func TestPastel(t *testing.T) {
+	for _, c := range []RGB{{1, 0, 0}, {0, 0.6, 0.3}, {0.2, 0.1, 0.9}} {
+		have, want := c.Pastel().ToHSL(), c.ToHSL()
+		if have.L <= want.L || have.S >= want.S {
+			t.Errorf("%s: have %v, want lighter and less saturated than %v", c.ToHTML(), have, want)
+		}
+		if hueDistance(have.H, want.H) > epsilonF {
+			t.Errorf("%s: hue moved from %f to %f", c.ToHTML(), want.H, have.H)
+		}
+	}
+	gray := RGB{0.3, 0.3, 0.3}.Pastel()
+	if gray.R != gray.G || gray.G != gray.B {
+		t.Errorf("gray: have %v", gray)
+	}
+}
+
+func TestBorderColor(t *testing.T) {
+	const minDelta = 5
+	for _, c := range []RGB{white, black, {1, 1, 0}, {0, 0, 0.5}, {0.9, 0.3, 0.3}, {0.5, 0.5, 0.5}} {
+		border := c.BorderColor()
+		if d := DistanceCIEDE2000(c, border); d < minDelta {
+			t.Errorf("%s: border %s is only %.2f away", c.ToHTML(), border.ToHTML(), d)
+		}
+		fill, edge := c.ToLab().L, border.ToLab().L
+		if light := fill > 50; light && edge >= fill || !light && edge <= fill {
+			t.Errorf("%s: border L* %.1f goes the wrong way from %.1f", c.ToHTML(), edge, fill)
+		}
+	}
+
+	// vivid fills leave the gamut when shifted, and are pulled back in at the
+	// shifted lightness and the same hue rather than clipped
+	for _, c := range []RGB{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 0, 1}, {0, 1, 1}} {
+		fill, edge := c.ToLab(), c.BorderColor().ToLab()
+		if d := math.Abs(edge.L - fill.L); real.Diff(d, borderShift) > 0.1 {
+			t.Errorf("%s: border L* %.2f is %.2f from %.2f, want %d", c.ToHTML(), edge.L, d, fill.L, borderShift)
+		}
+		if d := math.Abs(hueDeg(edge.B, edge.A) - hueDeg(fill.B, fill.A)); min(d, 360-d) > 1 {
+			t.Errorf("%s: border hue %.1f°, want %.1f°", c.ToHTML(), hueDeg(edge.B, edge.A), hueDeg(fill.B, fill.A))
+		}
+	}
+}
+
+func TestClampAll(t *testing.T) {
+	in := []RGB{{-0.5, 0.5, 1.5}, {0.2, 0.3, 0.4}, {2, -1, 1}}
+	want := []RGB{{0, 0.5, 1}, {0.2, 0.3, 0.4}, {1, 0, 1}}
+	have := ClampAll(in)
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+	if in[0] != (RGB{-0.5, 0.5, 1.5}) {
+		t.Errorf("input modified: %v", in[0])
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	in := []RGB{{0.1, 0.2, 0.4}, {0.5, 0.25, 0}}
+	want := []RGB{{0.2, 0.4, 0.8}, {1, 0.5, 0}}
+	have := NormalizeAll(in)
+	for i := range want {
+		if !eqRGB(have[i], want[i]) {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+	over := NormalizeAll([]RGB{{2, 1, 0}})
+	if !eqRGB(over[0], RGB{1, 0.5, 0}) {
+		t.Errorf("over range: have %v, want %v", over[0], RGB{1, 0.5, 0})
+	}
+	if have := NormalizeAll([]RGB{black}); have[0] != black {
+		t.Errorf("black: have %v", have[0])
+	}
+}
+
+func TestLightnessSteps(t *testing.T) {
+	for _, c := range []RGB{{0.27, 0.51, 0.71}, {0.5, 0.5, 0.5}, {0.85, 0.8, 0.75}, {0.1, 0.15, 0.12}} {
+		for _, n := range []int{3, 5, 7} {
+			steps := c.LightnessSteps(n)
+			if len(steps) != n {
+				t.Fatalf("%s %d: have %d steps", c.ToHTML(), n, len(steps))
+			}
+			if !eqRGB(steps[n/2], c) {
+				t.Errorf("%s %d: center is %s", c.ToHTML(), n, steps[n/2].ToHTML())
+			}
+			gap := steps[1].ToLab().L - steps[0].ToLab().L
+			for i := 1; i < n; i++ {
+				d := steps[i].ToLab().L - steps[i-1].ToLab().L
+				if d <= 0 || real.Diff(d, gap) > 0.5 {
+					t.Errorf("%s %d: L* gap %d is %.2f, want %.2f", c.ToHTML(), n, i, d, gap)
+				}
+			}
+		}
+	}
+
+	// black and white leave no room on one side, so the shades run one way from them
+	for _, test := range []struct {
+		c   RGB
+		pos int
+	}{{black, 0}, {white, 4}} {
+		steps := test.c.LightnessSteps(5)
+		if steps[test.pos] != test.c {
+			t.Errorf("%s: have %v, want it at %d", test.c.ToHTML(), steps, test.pos)
+		}
+		for i := 1; i < len(steps); i++ {
+			if d := steps[i].ToLab().L - steps[i-1].ToLab().L; real.Diff(d, lightnessStep) > 0.5 {
+				t.Errorf("%s: L* gap %d is %.2f, want %d", test.c.ToHTML(), i, d, lightnessStep)
+			}
+		}
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	for _, test := range []struct {
+		c          HSL
+		dh, ds, dl float64
+	}{
+		{HSL{0.1, 0.5, 0.5}, 0.25, 0.1, -0.2},
+		{HSL{0.9, 0.8, 0.3}, 0.3, 0.5, -0.5},
+		{HSL{0.2, 0.1, 0.9}, -0.4, -0.3, 0.4},
+		{HSL{0.5, 0.5, 0.5}, 0, 0, 0},
+	} {
+		// one step at a time
+		want := test.c
+		want.H = wrapHue(want.H + test.dh)
+		want.S = clamp01(want.S + test.ds)
+		want.L = clamp01(want.L + test.dl)
+
+		have := test.c.Adjust(test.dh, test.ds, test.dl)
+		if real.Diff(have.H, want.H) > epsilonF || have.S != want.S || have.L != want.L {
+			t.Errorf("%v: have %v, want %v", test.c, have, want)
+		}
+		if rgb, want := test.c.ToRGB().Adjust(test.dh, test.ds, test.dl), have.ToRGB(); !eqColor(rgb, want) {
+			t.Errorf("%v as RGB: have %v, want %v", test.c, rgb, want)
+		}
+	}
+}
+
+func TestClamp(t *testing.T) {
+	for _, test := range []struct {
+		in, want RGB
+		valid    bool
+	}{
+		{RGB{0.2, 0.4, 0.6}, RGB{0.2, 0.4, 0.6}, true},
+		{black, black, true},
+		{white, white, true},
+		{RGB{10, 20, 30}, white, false},
+		{RGB{-0.1, 0.5, 1.0001}, RGB{0, 0.5, 1}, false},
+	} {
+		if have := test.in.Valid(); have != test.valid {
+			t.Errorf("%v valid: have %v, want %v", test.in, have, test.valid)
+		}
+		if have := test.in.Clamp(); have != test.want || !have.Valid() {
+			t.Errorf("%v: have %v, want %v", test.in, have, test.want)
+		}
+	}
+	if c := (RGB{math.NaN(), 0, 0}); c.Valid() {
+		t.Errorf("%v: have valid", c)
+	}
+
+	if have, want := (HSL{1.2, -0.5, 0.5}).Clamp(), (HSL{1, 0, 0.5}); have != want {
+		t.Errorf("HSL: have %v, want %v", have, want)
+	}
+}
+
+func TestQuantizeBits(t *testing.T) {
+	for range nTrials {
+		c := Random[RGB]().(RGB)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			for _, v := range []float64{c.QuantizeBits(1).R, c.QuantizeBits(1).G, c.QuantizeBits(1).B} {
+				if v != 0 && v != 1 {
+					t.Errorf("1 bit: have channel %f, want 0 or 1", v)
+				}
+			}
+			if have := c.QuantizeBits(8); have.ToHTML() != c.ToHTML() || real.Diff(have.R, c.R) > 0.5/255 {
+				t.Errorf("8 bits: have %v, want about %v", have, c)
+			}
+			if have, want := c.QuantizeBits(12), c.QuantizeBits(8); have != want {
+				t.Errorf("12 bits: have %v, want %v as for 8", have, want)
+			}
+		})
+	}
+
+	// 2 bits gives levels 0, 1/3, 2/3, and 1
+	if have, want := (RGB{0.1, 0.4, 0.9}).QuantizeBits(2), (RGB{0, 1 / 3.0, 1}); have != want {
+		t.Errorf("2 bits: have %v, want %v", have, want)
+	}
+}
+
+func TestAccentComplement(t *testing.T) {
+	const boost, shift = 0.2, 0.1
+	for _, c := range []RGB{{0.6, 0.4, 0.3}, {0.2, 0.3, 0.6}, {0.45, 0.5, 0.3}} {
+		src, accent := c.ToHSL(), c.AccentComplement(boost, shift).ToHSL()
+		if d := hueDistance(accent.H, src.Complement().H); d > 1e-6 {
+			t.Errorf("%s: hue %.4f is %.4f off the complement %.4f", c.ToHTML(), accent.H, d, src.Complement().H)
+		}
+		if real.Diff(accent.S, src.S+boost) > 1e-6 {
+			t.Errorf("%s: have saturation %.4f, want %.4f", c.ToHTML(), accent.S, src.S+boost)
+		}
+		if real.Diff(accent.L, src.L+shift) > 1e-6 {
+			t.Errorf("%s: have lightness %.4f, want %.4f", c.ToHTML(), accent.L, src.L+shift)
+		}
+	}
+}
+
+func TestLightenDarken(t *testing.T) {
+	if have := white.Lighten(0.2); have != white {
+		t.Errorf("lighten white: have %v", have)
+	}
+	if have := black.Darken(0.2); have != black {
+		t.Errorf("darken black: have %v", have)
+	}
+
+	c := HSL{0.6, 0.5, 0.4}
+	if have := c.Lighten(0.25); real.Diff(have.L, 0.65) > epsilonF || have.H != c.H || have.S != c.S {
+		t.Errorf("lighten: have %v, want L 0.65", have)
+	}
+	if have := c.Darken(0.25); real.Diff(have.L, 0.15) > epsilonF || have.H != c.H || have.S != c.S {
+		t.Errorf("darken: have %v, want L 0.15", have)
+	}
+	if have := c.Lighten(2); have.L != 1 {
+		t.Errorf("lighten past white: have %v", have)
+	}
+	if have := c.Darken(2); have.L != 0 {
+		t.Errorf("darken past black: have %v", have)
+	}
+	if have, want := c.ToRGB().Lighten(0.1), c.Lighten(0.1).ToRGB(); !eqColor(have, want) {
+		t.Errorf("RGB lighten: have %v, want %v", have, want)
+	}
+}
+
+func TestSaturate(t *testing.T) {
+	c := HSL{0.3, 0.5, 0.5}
+	if have := c.Saturate(0.2); real.Diff(have.S, 0.7) > epsilonF || have.H != c.H || have.L != c.L {
+		t.Errorf("saturate: have %v, want S 0.7", have)
+	}
+	if have := c.Desaturate(0.2); real.Diff(have.S, 0.3) > epsilonF || have.H != c.H || have.L != c.L {
+		t.Errorf("desaturate: have %v, want S 0.3", have)
+	}
+	if have := c.Saturate(1); have.S != 1 {
+		t.Errorf("oversaturate: have %v", have)
+	}
+	if have := c.Desaturate(1); have.S != 0 {
+		t.Errorf("overdesaturate: have %v", have)
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	for range nTrials {
+		c := Random[RGB]().(RGB)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			gray := c.Grayscale()
+			if gray.R != gray.G || gray.G != gray.B {
+				t.Errorf("have %v, want a gray", gray)
+			}
+			if real.Diff(gray.Luminance(), c.Luminance()) > epsilonF {
+				t.Errorf("luminance: have %f, want %f", gray.Luminance(), c.Luminance())
+			}
+			if again := gray.Grayscale(); !eqRGB(again, gray) {
+				t.Errorf("not idempotent: %v became %v", gray, again)
+			}
+		})
+	}
+
+	// green weighs far more than blue
+	if g, b := (RGB{0, 1, 0}).Grayscale(), (RGB{0, 0, 1}).Grayscale(); g.R <= b.R {
+		t.Errorf("green %v isn't lighter than blue %v", g, b)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	if have := black.Invert(); have != white {
+		t.Errorf("black: have %v, want %v", have, white)
+	}
+	for range nTrials {
+		c := Random[RGB]().(RGB)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			if have := c.Invert().Invert(); !eqRGB(have, c) {
+				t.Errorf("twice: have %v, want %v", have, c)
+			}
+		})
+	}
+}
+
+func TestComplement(t *testing.T) {
+	for range nTrials {
+		c := Random[HSL]().(HSL)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			once := c.Complement()
+			if d := hueDistance(once.H, c.H); real.Diff(d, 0.5) > epsilonF {
+				t.Errorf("once: hue moved by %f, want 0.5", d)
+			}
+			if twice := c.Complement().Complement(); real.Diff(twice.H, c.H) > epsilonF || twice.S != c.S || twice.L != c.L {
+				t.Errorf("twice: have %v, want %v", twice, c)
+			}
+		})
+	}
+
+	// the two differ: a dark red inverts to a light cyan but complements to a dark one
+	red := RGB{0.5, 0, 0}
+	if inv, comp := red.Invert(), red.Complement(); inv.ToHSL().L <= comp.ToHSL().L {
+		t.Errorf("invert %v isn't lighter than complement %v", inv, comp)
+	}
+	gray := RGB{0.2, 0.2, 0.2}
+	if have := gray.Complement(); !eqRGB(have, gray) {
+		t.Errorf("gray complement: have %v, want %v", have, gray)
+	}
+}
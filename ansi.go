@@ -0,0 +1,40 @@
+package color
+
+// ANSIPalette holds the 16 standard terminal colors, as Windows Terminal's default
+// Campbell scheme renders them. Unlike xterm's defaults, whose bright red is pure
+// red, its normal colors are the vivid ones, so the primaries map to the normal codes.
+// Entry i has the foreground code 30+i for i < 8 and 90+i-8 for the bright colors after.
+var ANSIPalette = Palette{
+	FromHexInt(0x0c0c0c), // black
+	FromHexInt(0xc50f1f), // red
+	FromHexInt(0x13a10e), // green
+	FromHexInt(0xc19c00), // yellow
+	FromHexInt(0x0037da), // blue
+	FromHexInt(0x881798), // magenta
+	FromHexInt(0x3a96dd), // cyan
+	FromHexInt(0xcccccc), // white
+	FromHexInt(0x767676), // bright black
+	FromHexInt(0xe74856), // bright red
+	FromHexInt(0x16c60c), // bright green
+	FromHexInt(0xf9f1a5), // bright yellow
+	FromHexInt(0x3b78ff), // bright blue
+	FromHexInt(0xb4009e), // bright magenta
+	FromHexInt(0x61d6d6), // bright cyan
+	FromHexInt(0xf2f2f2), // bright white
+}
+
+// ANSI16 returns the foreground code, 30-37 or 90-97, of the entry in ANSIPalette
+// nearest to c by CIEDE2000
+func (c RGB) ANSI16() int {
+	lab := c.ToLab()
+	best, dist := 0, ciede2000(lab, ANSIPalette[0].ToLab())
+	for i, p := range ANSIPalette[1:] {
+		if d := ciede2000(lab, p.ToLab()); d < dist {
+			best, dist = i+1, d
+		}
+	}
+	if best < 8 {
+		return 30 + best
+	}
+	return 90 + best - 8
+}
@@ -0,0 +1,33 @@
+package color
+
+import "testing"
+
+func TestANSI16(t *testing.T) {
+	for _, test := range []struct {
+		c    RGB
+		want int
+	}{
+		{RGB{1, 0, 0}, 31},
+		{RGB{0.8, 0, 0}, 31},
+		{RGB{0.9, 0.3, 0.35}, 91},
+		{RGB{0.05, 0.05, 0.05}, 30},
+		{RGB{0.1, 0.6, 0.1}, 32},
+		{RGB{0, 1, 0}, 92},
+		{RGB{0.1, 0.1, 0.95}, 34},
+		{RGB{0.5, 0.5, 0.5}, 90},
+		{RGB{1, 1, 1}, 97},
+	} {
+		if have := test.c.ANSI16(); have != test.want {
+			t.Errorf("%s: have %d, want %d", test.c.ToHTML(), have, test.want)
+		}
+	}
+	for i, c := range ANSIPalette {
+		want := 30 + i
+		if i >= 8 {
+			want = 90 + i - 8
+		}
+		if have := c.ANSI16(); have != want {
+			t.Errorf("%s: have %d, want %d", c.ToHTML(), have, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package color
+
+import "image/color"
+
+// CMYK is a color in the naive, device independent CMYK model used by the
+// standard library's color.CMYK
+type CMYK struct {
+	C, M, Y, K float64 // Cyan, Magenta, Yellow, Key (black) values in [0, 1]
+}
+
+// ToCMYK converts c to CMYK, putting as much as possible into K.
+// Black is all K with no C, M, or Y.
+func (c RGB) ToCMYK() CMYK {
+	w := max(c.R, c.G, c.B)
+	if w == 0 {
+		return CMYK{0, 0, 0, 1}
+	}
+	return CMYK{(w - c.R) / w, (w - c.G) / w, (w - c.B) / w, 1 - w}
+}
+
+// ToRGB converts c to RGB
+func (c CMYK) ToRGB() RGB {
+	return RGB{(1 - c.C) * (1 - c.K), (1 - c.M) * (1 - c.K), (1 - c.Y) * (1 - c.K)}
+}
+
+func (c CMYK) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
+
+var CMYKModel color.Model = color.ModelFunc(cmykModel)
+
+func cmykModel(c color.Color) color.Color {
+	return rgbModel(c).(RGB).ToCMYK()
+}
@@ -0,0 +1,54 @@
+package color
+
+import (
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var _ color.Color = CMYK{}
+
+func TestCMYKBlack(t *testing.T) {
+	if have, want := black.ToCMYK(), (CMYK{0, 0, 0, 1}); have != want {
+		t.Errorf("have %v, want %v", have, want)
+	}
+	if have := (CMYK{0.3, 0.6, 0.9, 1}).ToRGB(); have != black {
+		t.Errorf("full K: have %v, want %v", have, black)
+	}
+}
+
+func TestCMYKRoundTrip(t *testing.T) {
+	for range nTrials {
+		c := Random[RGB]().(RGB)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			if have := c.ToCMYK().ToRGB(); !eqRGB(have, c) {
+				t.Errorf("have %v, want %v", have, c)
+			}
+		})
+	}
+}
+
+func TestCMYKStdlib(t *testing.T) {
+	for range nTrials {
+		r, g, b := uint8(rand.Uint32()), uint8(rand.Uint32()), uint8(rand.Uint32())
+		c := RGB{}.constructor(r, g, b)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			var std color.CMYK
+			std.C, std.M, std.Y, std.K = color.RGBToCMYK(r, g, b)
+			have := c.ToCMYK()
+			for i, pair := range [][2]float64{
+				{have.C, float64(std.C)}, {have.M, float64(std.M)},
+				{have.Y, float64(std.Y)}, {have.K, float64(std.K)},
+			} {
+				if real.Diff(pair[0]*0xff, pair[1]) > 1 {
+					t.Errorf("component %d: have %f, want %d/255", i, pair[0], uint8(pair[1]))
+				}
+			}
+			if from := CMYKModel.Convert(std).(CMYK); !eqColor(from, std) {
+				t.Errorf("model: have %v, want %v", from, std)
+			}
+		})
+	}
+}
@@ -13,7 +13,8 @@
 
 	Also, color types don't verify their validity before converting. If you do
 	something like RGB{10,20,30}.ToHSL() the results will be undefined. All
-	values must be between 0 and 1.
+	values must be between 0 and 1. Use Valid to check a color and Clamp to
+	force one into range, say after blending.
 */
 
 package color
@@ -23,6 +24,8 @@ import (
 	"fmt"
 	"image/color"
 	"math/rand"
+	"strconv"
+	"strings"
 
 	"github.com/kendfss/oprs/math/real"
 )
@@ -40,22 +43,37 @@ func (RGB) constructor(r, g, b uint8) RGB {
 	}
 }
 
-// Takes a string like '#123456' or 'ABCDEF' and returns an RGB
+// isHex reports whether s is a nonempty run of hex digits
+func isHex(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789abcdefABCDEF") == ""
+}
+
+// Takes a string like '#123456' or 'ABCDEF', or the CSS shorthand '#abc' for
+// '#aabbcc', and returns an RGB. Surrounding whitespace is ignored.
 func HTMLToRGB(in string) (RGB, error) {
-	if in[0] == '#' {
-		in = in[1:]
+	in = strings.TrimSpace(in)
+	if in == "" {
+		return RGB{}, errors.New("Empty color string")
+	}
+	hex := strings.TrimPrefix(in, "#")
+	if !isHex(hex) {
+		return RGB{}, fmt.Errorf("Invalid hex color %q", in)
 	}
 
-	if len(in) != 6 {
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
 		return RGB{}, errors.New("Invalid string length")
 	}
 
-	var r, g, b byte
-	if n, err := fmt.Sscanf(in, "%2x%2x%2x", &r, &g, &b); err != nil || n != 3 {
-		return RGB{}, err
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("Invalid hex color %q", in)
 	}
 
-	return RGB{float64(r) / 255, float64(g) / 255, float64(b) / 255}, nil
+	return FromHexInt(uint32(v)), nil
 }
 
 func (c RGB) ToHSL() HSL {
@@ -109,11 +127,44 @@ func (c RGB) ToHSL() HSL {
 	return HSL{h, s, l}
 }
 
-// A nudge to make truncation round to nearest number instead of flooring
-const delta = 1 / 512.0
+// SatModel selects a definition of saturation for RGB.Saturation
+type SatModel int
+
+const (
+	// HSLSat is saturation as in HSL, chroma relative to the most any color
+	// of the same lightness could have
+	HSLSat SatModel = iota
+	// HSVSat is saturation as in HSV, chroma relative to the value, the largest channel
+	HSVSat
+)
+
+// Saturation returns the saturation of c under the given model, in [0, 1].
+// Grays, black included, have a saturation of 0 under both.
+func (c RGB) Saturation(model SatModel) float64 {
+	M, m := max(c.R, c.G, c.B), min(c.R, c.G, c.B)
+	if M == m {
+		return 0
+	}
+	if model == HSVSat {
+		return (M - m) / M
+	}
+	return c.ToHSL().S
+}
 
+// ToHTML returns c as 6 hex digits, each channel rounded to the nearest byte
+// and clamped into range
 func (c RGB) ToHTML() string {
-	return fmt.Sprintf("%02x%02x%02x", byte((c.R+delta)*255), byte((c.G+delta)*255), byte((c.B+delta)*255))
+	return fmt.Sprintf("%02x%02x%02x", channel8(c.R), channel8(c.G), channel8(c.B))
+}
+
+// ToHexInt packs c into the low 24 bits of an integer, as in 0x336699
+func (c RGB) ToHexInt() uint32 {
+	return uint32(channel8(c.R))<<16 | uint32(channel8(c.G))<<8 | uint32(channel8(c.B))
+}
+
+// FromHexInt unpacks a color from the low 24 bits of v, ignoring the rest
+func FromHexInt(v uint32) RGB {
+	return RGB{}.constructor(uint8(v>>16), uint8(v>>8), uint8(v))
 }
 
 func (c RGB) RGBA() (r, g, b, a uint32) {
@@ -217,12 +268,39 @@ func New[T RGB | HSL](rh, gs, bl uint8) color.Color {
 	}
 }
 
+// Random returns a color with uniformly random components, drawn from the
+// global source of math/rand
 func Random[T RGB | HSL]() color.Color {
+	return random[T](rand.Float64)
+}
+
+// RandomFrom is like Random but draws from r, so a seeded source gives the
+// same sequence of colors every run
+func RandomFrom[T RGB | HSL](r *rand.Rand) color.Color {
+	return random[T](r.Float64)
+}
+
+// RandomHSL returns a color drawn from r with each component uniform over its
+// [min, max] range, such as high lightness and low saturation for pastels.
+// Each range must lie within [0, 1] with min <= max. The hue range does not
+// wrap, so reds either side of 0 take two calls.
+func RandomHSL(hRange, sRange, lRange [2]float64, r *rand.Rand) (HSL, error) {
+	var c [3]float64
+	for i, rng := range [3][2]float64{hRange, sRange, lRange} {
+		if !(0 <= rng[0] && rng[0] <= rng[1] && rng[1] <= 1) {
+			return HSL{}, fmt.Errorf("invalid %c range %v: want 0 <= min <= max <= 1", "hsl"[i], rng)
+		}
+		c[i] = rng[0] + r.Float64()*(rng[1]-rng[0])
+	}
+	return HSL{c[0], c[1], c[2]}, nil
+}
+
+func random[T RGB | HSL](float func() float64) color.Color {
 	switch any(new(T)).(type) {
 	case *RGB:
-		return RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		return RGB{float(), float(), float()}
 	case *HSL:
-		return HSL{rand.Float64(), rand.Float64(), rand.Float64()}
+		return HSL{float(), float(), float()}
 	default:
 		panic("impossible")
 	}
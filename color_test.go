@@ -4,6 +4,7 @@ import (
 	"image/color"
 	"math/rand"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/kendfss/but"
@@ -121,3 +122,137 @@ func TestHSLModel(t *testing.T) {
 		t.Errorf("mean %.0f, errors %6d", slices.Reduce(oprs.Add[float64], er), len(er))
 	}
 }
+
+func TestHexInt(t *testing.T) {
+	if have, want := FromHexInt(0xff0000), (RGB{1, 0, 0}); have != want {
+		t.Errorf("0xff0000: have %v, want %v", have, want)
+	}
+	if have := FromHexInt(0xab336699).ToHTML(); have != "336699" {
+		t.Errorf("high bits: have %s, want 336699", have)
+	}
+	for _, v := range []uint32{0, 0xff0000, 0x336699, 0xffffff, 0x0a0b0c} {
+		if have := FromHexInt(v).ToHexInt(); have != v {
+			t.Errorf("%#06x: round trips to %#06x", v, have)
+		}
+	}
+}
+
+func TestHTMLToRGB(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want RGB
+	}{
+		{"#336699", RGB{0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}},
+		{"336699", RGB{0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}},
+		{" #ABCDEF\n", RGB{0xab / 255.0, 0xcd / 255.0, 0xef / 255.0}},
+		{"#abc", RGB{0xaa / 255.0, 0xbb / 255.0, 0xcc / 255.0}},
+		{"abc", RGB{0xaa / 255.0, 0xbb / 255.0, 0xcc / 255.0}},
+		{"#F0a", RGB{1, 0, 0xaa / 255.0}},
+	} {
+		if have, err := HTMLToRGB(test.in); err != nil || have != test.want {
+			t.Errorf("%q: have %v %v, want %v", test.in, have, err, test.want)
+		}
+	}
+	for _, in := range []string{"", " ", "#", "#12", "#1234", "#12345", "#1234567", "12345g", "zzzzzz", "#ab"} {
+		if have, err := HTMLToRGB(in); err == nil {
+			t.Errorf("%q: have %v, want an error", in, have)
+		}
+	}
+	// the error names what the caller wrote, not its 6 digit expansion
+	if _, err := HTMLToRGB("red"); err == nil || !strings.Contains(err.Error(), `"red"`) {
+		t.Errorf("red: have %v, want an error naming %q", err, "red")
+	}
+}
+
+func TestToHTMLRoundTrip(t *testing.T) {
+	// every 8 bit color, or a spread of them in short mode
+	step := 1
+	if testing.Short() {
+		step = 251
+	}
+	for v := 0; v < 1<<24; v += step {
+		c := FromHexInt(uint32(v))
+		html := c.ToHTML()
+		have, err := HTMLToRGB(html)
+		if err != nil || have != c {
+			t.Fatalf("%s: have %v %v, want %v", html, have, err, c)
+		}
+	}
+	for _, test := range []struct {
+		c    RGB
+		want string
+	}{
+		{RGB{0.9999, 1.2, -0.1}, "ffff00"},
+		{RGB{0.5 / 255, 1.49 / 255, 254.5 / 255}, "0101ff"},
+	} {
+		if have := test.c.ToHTML(); have != test.want {
+			t.Errorf("%v: have %s, want %s", test.c, have, test.want)
+		}
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	for _, test := range []struct {
+		c        RGB
+		hsl, hsv float64
+	}{
+		{RGB{0.75, 0.25, 0.25}, 0.5, 2 / 3.0},
+		{RGB{0.5, 0.25, 0.25}, 1 / 3.0, 0.5},
+		{RGB{1, 0, 0}, 1, 1},
+		{RGB{1, 0.8, 0.8}, 1, 0.2},
+		{RGB{0.5, 0.5, 0.5}, 0, 0},
+		{RGB{0, 0, 0}, 0, 0},
+	} {
+		if have := test.c.Saturation(HSLSat); real.Diff(have, test.hsl) > epsilonF {
+			t.Errorf("%s HSL: have %f, want %f", test.c.ToHTML(), have, test.hsl)
+		}
+		if have := test.c.Saturation(HSVSat); real.Diff(have, test.hsv) > epsilonF {
+			t.Errorf("%s HSV: have %f, want %f", test.c.ToHTML(), have, test.hsv)
+		}
+	}
+}
+
+func TestRandomFrom(t *testing.T) {
+	const seed = 272
+	a, b := rand.New(rand.NewSource(seed)), rand.New(rand.NewSource(seed))
+	for i := range 8 {
+		if x, y := RandomFrom[RGB](a), RandomFrom[RGB](b); x != y {
+			t.Errorf("RGB %d: have %v and %v from the same seed", i, x, y)
+		}
+		if x, y := RandomFrom[HSL](a), RandomFrom[HSL](b); x != y {
+			t.Errorf("HSL %d: have %v and %v from the same seed", i, x, y)
+		}
+	}
+
+	// the components are the source's next three floats, in order
+	r, want := rand.New(rand.NewSource(seed)), rand.New(rand.NewSource(seed))
+	if have := RandomFrom[HSL](r).(HSL); have != (HSL{want.Float64(), want.Float64(), want.Float64()}) {
+		t.Errorf("have %v, want the first three floats of the source", have)
+	}
+}
+
+func TestRandomHSL(t *testing.T) {
+	r := rand.New(rand.NewSource(275))
+	h, s, l := [2]float64{0.5, 0.7}, [2]float64{0.1, 0.3}, [2]float64{0.8, 0.9}
+	for range 100 {
+		c, err := RandomHSL(h, s, l, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.H < h[0] || c.H > h[1] || c.S < s[0] || c.S > s[1] || c.L < l[0] || c.L > l[1] {
+			t.Errorf("have %v, want within %v %v %v", c, h, s, l)
+		}
+	}
+
+	// an empty range pins its component
+	if c, err := RandomHSL([2]float64{0.25, 0.25}, [2]float64{0, 1}, [2]float64{1, 1}, r); err != nil || c.H != 0.25 || c.L != 1 {
+		t.Errorf("have %v %v, want hue 0.25 and lightness 1", c, err)
+	}
+
+	full := [2]float64{0, 1}
+	for _, bad := range [][2]float64{{0.6, 0.4}, {-0.1, 0.5}, {0.5, 1.1}} {
+		if c, err := RandomHSL(full, bad, full, r); err == nil {
+			t.Errorf("%v: have %v, want an error", bad, c)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package color
+
+import "math"
+
+// Linearize converts an sRGB encoded channel value in [0, 1] to linear light
+func Linearize(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// Delinearize converts a linear light channel value in [0, 1] to sRGB encoding.
+// It is the inverse of Linearize.
+func Delinearize(v float64) float64 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// Luminance returns the relative luminance of c as defined by WCAG 2.1,
+// using the Rec. 709 coefficients on the linearized channels
+func (c RGB) Luminance() float64 {
+	return 0.2126*Linearize(c.R) + 0.7152*Linearize(c.G) + 0.0722*Linearize(c.B)
+}
+
+// ContrastRatio returns the WCAG 2.1 contrast ratio between a and b, in [1, 21]
+func ContrastRatio(a, b RGB) float64 {
+	return contrast(a.Luminance(), b.Luminance())
+}
+
+// The WCAG 2.1 minimum contrast ratios for normal and large text
+const (
+	aaNormal  = 4.5
+	aaLarge   = 3
+	aaaNormal = 7
+	aaaLarge  = 4.5
+)
+
+// MeetsAA reports whether fg on bg meets WCAG 2.1 level AA, a contrast ratio
+// of at least 4.5:1, or 3:1 for large text
+func MeetsAA(fg, bg RGB, largeText bool) bool {
+	if largeText {
+		return ContrastRatio(fg, bg) >= aaLarge
+	}
+	return ContrastRatio(fg, bg) >= aaNormal
+}
+
+// MeetsAAA reports whether fg on bg meets WCAG 2.1 level AAA, a contrast ratio
+// of at least 7:1, or 4.5:1 for large text
+func MeetsAAA(fg, bg RGB, largeText bool) bool {
+	if largeText {
+		return ContrastRatio(fg, bg) >= aaaLarge
+	}
+	return ContrastRatio(fg, bg) >= aaaNormal
+}
+
+// contrast computes the WCAG contrast ratio of a pair of relative luminances
+func contrast(l1, l2 float64) float64 {
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// BestTextColor returns black or white, whichever contrasts more with bg
+func BestTextColor(bg RGB) RGB {
+	black, white := RGB{0, 0, 0}, RGB{1, 1, 1}
+	if ContrastRatio(black, bg) >= ContrastRatio(white, bg) {
+		return black
+	}
+	return white
+}
+
+// ReadableVariant returns brand with its CIELAB lightness moved as little as
+// possible, darker or lighter, for it to contrast with bg by at least ratio.
+// Hue is kept, and chroma too unless the new lightness can't hold it in sRGB.
+// Brand colors that already pass come back untouched. When no lightness can
+// reach the ratio, the result is whichever of black and white contrasts more.
+func ReadableVariant(brand, bg RGB, ratio float64) RGB {
+	if ContrastRatio(brand, bg) >= ratio {
+		return brand
+	}
+
+	lab := brand.ToLab()
+	lbg := bg.Luminance()
+	best, shift := BestTextColor(bg), math.Inf(1)
+	for _, dir := range []struct {
+		y, step float64 // the luminance that just meets the ratio, and the way to L* that improves on it
+	}{
+		{(lbg+0.05)/ratio - 0.05, -0.05},
+		{ratio*(lbg+0.05) - 0.05, 0.05},
+	} {
+		if dir.y < 0 || dir.y > 1 {
+			continue
+		}
+		// luminance and CIELAB's Y round slightly differently,
+		// so step on until the ratio is met in luminance terms
+		l := 116*labF(dir.y) - 16
+		variant := Lab{l, lab.A, lab.B}.fitGamut()
+		for ContrastRatio(variant, bg) < ratio && l > 0 && l < 100 {
+			l += dir.step
+			variant = Lab{l, lab.A, lab.B}.fitGamut()
+		}
+		if ContrastRatio(variant, bg) < ratio {
+			continue
+		}
+		if d := math.Abs(l - lab.L); d < shift {
+			best, shift = variant, d
+		}
+	}
+	return best
+}
+
+// WithOnColor pairs c, as a container color in the Material sense, with an
+// on-color for text and icons drawn on it: a tone of c's own hue that contrasts
+// with it by at least 4.5:1, or black or white where no tone of the hue can.
+func (c RGB) WithOnColor() (container, onColor RGB) {
+	return c, ReadableVariant(c, c, minTextContrast)
+}
+
+// The saturation and the light and dark lightnesses of suggested backgrounds
+const (
+	backgroundS      = 0.08
+	backgroundLightL = 0.96
+	backgroundDarkL  = 0.1
+)
+
+// SuggestBackground returns a near neutral background for c, such as a logo's
+// dominant color, to stand out on: a very light or very dark tint of c's own
+// hue, so as not to clash, whichever contrasts more with c
+func (c RGB) SuggestBackground() RGB {
+	h := c.ToHSL().H
+	light := HSL{h, backgroundS, backgroundLightL}.ToRGB()
+	dark := HSL{h, backgroundS, backgroundDarkL}.ToRGB()
+	if ContrastRatio(c, light) >= ContrastRatio(c, dark) {
+		return light
+	}
+	return dark
+}
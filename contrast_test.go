@@ -0,0 +1,122 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReadableVariant(t *testing.T) {
+	const ratio = 4.5
+	for _, test := range []struct {
+		brand, bg RGB
+	}{
+		{RGB{1, 0.4, 0}, white},
+		{RGB{0.2, 0.8, 0.9}, white},
+		{RGB{0.1, 0.2, 0.6}, black},
+		{RGB{0.6, 0.1, 0.7}, RGB{0.2, 0.2, 0.2}},
+	} {
+		variant := ReadableVariant(test.brand, test.bg, ratio)
+		have := ContrastRatio(variant, test.bg)
+		if have < ratio || have > ratio+0.1 {
+			t.Errorf("%s on %s: %s has contrast %.3f, want just over %.1f", test.brand.ToHTML(), test.bg.ToHTML(), variant.ToHTML(), have, ratio)
+		}
+		b, v := test.brand.ToLab(), variant.ToLab()
+		if d := hueDistance(math.Atan2(b.B, b.A)/(2*math.Pi), math.Atan2(v.B, v.A)/(2*math.Pi)); d > 0.02 {
+			t.Errorf("%s on %s: hue moved by %.3f turns to %s", test.brand.ToHTML(), test.bg.ToHTML(), d, variant.ToHTML())
+		}
+	}
+
+	if have := ReadableVariant(black, white, ratio); have != black {
+		t.Errorf("passing color changed to %v", have)
+	}
+}
+
+func TestWithOnColor(t *testing.T) {
+	for _, c := range []RGB{
+		{0.4, 0.3, 0.9}, {0.9, 0.85, 1}, {0.1, 0.4, 0.2}, {1, 0.6, 0},
+		{0.5, 0.5, 0.5}, black, white,
+	} {
+		container, on := c.WithOnColor()
+		if container != c {
+			t.Errorf("%s: container changed to %s", c.ToHTML(), container.ToHTML())
+		}
+		if r := ContrastRatio(container, on); r < minTextContrast {
+			t.Errorf("%s: on-color %s has contrast %.2f, want at least %.1f", c.ToHTML(), on.ToHTML(), r, minTextContrast)
+		}
+	}
+
+	// a mid tone gets a tone of its own hue rather than black or white
+	c := RGB{0.4, 0.3, 0.9}
+	if _, on := c.WithOnColor(); on == black || on == white {
+		t.Errorf("%s: have %s, want a tone of its hue", c.ToHTML(), on.ToHTML())
+	}
+}
+
+func TestSuggestBackground(t *testing.T) {
+	const minContrast = 3 // WCAG's bar for graphics
+	for _, c := range []RGB{
+		{0.9, 0.1, 0.1}, {0.1, 0.3, 0.8}, {1, 0.85, 0}, {0.2, 0.7, 0.3},
+		{0.5, 0.5, 0.5}, black, white,
+	} {
+		bg := c.SuggestBackground()
+		if r := ContrastRatio(c, bg); r < minContrast {
+			t.Errorf("%s: background %s has contrast %.2f, want at least %d", c.ToHTML(), bg.ToHTML(), r, minContrast)
+		}
+		if s := bg.ToHSL().S; s > backgroundS+epsilonF {
+			t.Errorf("%s: background %s has saturation %.2f, want at most %.2f", c.ToHTML(), bg.ToHTML(), s, backgroundS)
+		}
+	}
+	if bg := (RGB{1, 0.85, 0}).SuggestBackground(); bg.ToHSL().L > 0.5 {
+		t.Errorf("yellow: have light background %s, want dark", bg.ToHTML())
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	if have := ContrastRatio(black, white); math.Abs(have-21) > epsilonF {
+		t.Errorf("black on white: have %f, want 21", have)
+	}
+	if have := ContrastRatio(white, black); math.Abs(have-21) > epsilonF {
+		t.Errorf("white on black: have %f, want 21", have)
+	}
+	for range nTrials {
+		c := Random[RGB]().(RGB)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			if have := ContrastRatio(c, c); have != 1 {
+				t.Errorf("self: have %f, want 1", have)
+			}
+		})
+	}
+}
+
+func TestMeetsWCAG(t *testing.T) {
+	gray := func(hex string) RGB {
+		c, _ := HTMLToRGB(hex)
+		return c
+	}
+	for _, test := range []struct {
+		fg                         RGB
+		aa, aaLarge, aaa, aaaLarge bool
+	}{
+		{black, true, true, true, true},
+		{gray("595959"), true, true, true, true},    // 7.00:1
+		{gray("5a5a5a"), true, true, false, true},   // 6.90:1
+		{gray("767676"), true, true, false, true},   // 4.54:1
+		{gray("777777"), false, true, false, false}, // 4.48:1
+		{gray("949494"), false, true, false, false}, // 3.03:1
+		{gray("959595"), false, false, false, false},
+	} {
+		for _, check := range []struct {
+			name       string
+			have, want bool
+		}{
+			{"AA", MeetsAA(test.fg, white, false), test.aa},
+			{"AA large", MeetsAA(test.fg, white, true), test.aaLarge},
+			{"AAA", MeetsAAA(test.fg, white, false), test.aaa},
+			{"AAA large", MeetsAAA(test.fg, white, true), test.aaaLarge},
+		} {
+			if check.have != check.want {
+				t.Errorf("%s on white, %s: have %v, want %v (ratio %.2f)", test.fg.ToHTML(), check.name, check.have, check.want, ContrastRatio(test.fg, white))
+			}
+		}
+	}
+}
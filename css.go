@@ -0,0 +1,241 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrentColor is returned by Parse for the CSS currentColor keyword,
+// whose value depends on the element it is used on and so can't be resolved here
+var ErrCurrentColor = errors.New("currentColor depends on its context")
+
+// Parse reads a color written in any of the forms the package understands:
+//   - hex strings as accepted by HTMLToRGB, or with alpha by HTMLToRGBA
+//   - CSS color names as accepted by ByName
+//   - the CSS functions accepted by ParseCSS
+//   - the CSS keyword transparent, which yields a fully transparent color
+//   - the CSS keyword currentColor, which yields a nil color and ErrCurrentColor
+//
+// Functions yield an RGB, or an RGBA when they are given an alpha.
+// Keywords and function names are matched case insensitively and surrounding
+// whitespace is ignored.
+func Parse(s string) (color.Color, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "transparent":
+		return RGBA{}, nil
+	case "currentcolor":
+		return nil, ErrCurrentColor
+	}
+	if name, args, ok := cssFunc(s); ok {
+		return parseCSSFunc(name, args)
+	}
+	var (
+		c   color.Color
+		err error
+	)
+	if hex := strings.TrimPrefix(s, "#"); len(hex) == 4 || len(hex) == 8 {
+		c, err = HTMLToRGBA(s)
+	} else {
+		c, err = HTMLToRGB(s)
+	}
+	if err != nil && !strings.HasPrefix(s, "#") {
+		if named, nameErr := ByName(s); nameErr == nil {
+			return named, nil
+		}
+	}
+	return c, err
+}
+
+// ParseCSS reads a color written in CSS functional notation:
+//   - rgb() and rgba(), with channels all numbers in [0, 255] or all percentages
+//   - hsl() and hsla(), with a hue in degrees or another CSS angle unit, wrapped
+//     onto the wheel, and saturation and lightness as percentages
+//   - oklab() and oklch(), mapped into the sRGB gamut
+//
+// Arguments may be separated by commas, or by spaces with any alpha following
+// a slash, as in "rgb(255 0 128 / 50%)". Out of range channels are clamped as
+// CSS does. The result is an RGB, or an HSL for hsl(), or an RGBA whenever an
+// alpha in [0, 1] is given.
+func ParseCSS(s string) (color.Color, error) {
+	name, args, ok := cssFunc(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("%q is not a CSS color function", s)
+	}
+	return parseCSSFunc(name, args)
+}
+
+// cssFunc splits a CSS function call like "oklch(70% 0.15 180 / 0.5)" into its
+// lower cased name and arguments. Arguments are separated by commas or else by
+// spaces, in which case an alpha may follow a slash and is returned last.
+// ok is false when s isn't shaped like a function call.
+func cssFunc(s string) (name string, args []string, ok bool) {
+	open := strings.IndexByte(s, '(')
+	if open <= 0 || !strings.HasSuffix(s, ")") {
+		return "", nil, false
+	}
+	name = strings.ToLower(strings.TrimSpace(s[:open]))
+	body := strings.TrimSpace(s[open+1 : len(s)-1])
+	if strings.Contains(body, ",") {
+		for _, arg := range strings.Split(body, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		return name, args, true
+	}
+	channels, alpha, slash := strings.Cut(body, "/")
+	args = strings.Fields(channels)
+	if slash {
+		args = append(args, strings.TrimSpace(alpha))
+	}
+	return name, args, true
+}
+
+// cssComponent parses a CSS number or percentage, with 100% standing for full
+func cssComponent(arg string, full float64) (v float64, percent bool, err error) {
+	if num, ok := strings.CutSuffix(arg, "%"); ok {
+		v, err = strconv.ParseFloat(num, 64)
+		return v / 100 * full, true, err
+	}
+	v, err = strconv.ParseFloat(arg, 64)
+	return v, false, err
+}
+
+// cssHue parses a CSS hue, a bare number of degrees or an angle in deg, rad,
+// grad, or turn units, and returns it wrapped into the package's [0, 1) scale
+func cssHue(arg string) (float64, error) {
+	arg = strings.ToLower(arg)
+	perTurn := 360.0
+	for _, unit := range []struct {
+		suffix  string
+		perTurn float64
+	}{{"deg", 360}, {"grad", 400}, {"rad", 2 * math.Pi}, {"turn", 1}} {
+		if num, ok := strings.CutSuffix(arg, unit.suffix); ok {
+			arg, perTurn = num, unit.perTurn
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(arg, 64)
+	return wrapHue(v / perTurn), err
+}
+
+// cssAlpha parses a CSS alpha value, a number or percentage, clamped into [0, 1]
+func cssAlpha(arg string) (float64, error) {
+	a, _, err := cssComponent(arg, 1)
+	return clamp01(a), err
+}
+
+// parseCSSFunc evaluates a CSS color function split up by cssFunc
+func parseCSSFunc(name string, args []string) (color.Color, error) {
+	if len(args) != 3 && len(args) != 4 {
+		return nil, fmt.Errorf("%s() takes 3 components and an optional alpha, got %d arguments", name, len(args))
+	}
+
+	var c RGB
+	switch name {
+	case "rgb", "rgba":
+		var ch [3]float64
+		var percent [3]bool
+		for i := range ch {
+			v, p, err := cssComponent(args[i], 0xff)
+			if err != nil {
+				return nil, fmt.Errorf("%s(): %w", name, err)
+			}
+			ch[i], percent[i] = clamp01(v/0xff), p
+		}
+		if percent[0] != percent[1] || percent[1] != percent[2] {
+			return nil, fmt.Errorf("%s(): channels mix numbers and percentages", name)
+		}
+		c = RGB{ch[0], ch[1], ch[2]}
+	case "hsl", "hsla":
+		h, err := cssHue(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s(): %w", name, err)
+		}
+		var sl [2]float64
+		for i := range sl {
+			v, percent, err := cssComponent(args[i+1], 1)
+			if err != nil {
+				return nil, fmt.Errorf("%s(): %w", name, err)
+			}
+			if !percent {
+				return nil, fmt.Errorf("%s(): saturation and lightness must be percentages, got %s", name, args[i+1])
+			}
+			sl[i] = clamp01(v)
+		}
+		hsl := HSL{h, sl[0], sl[1]}
+		if len(args) == 3 {
+			return hsl, nil
+		}
+		c = hsl.ToRGB()
+	case "oklab", "oklch":
+		// chroma and the a, b axes all treat 100% as 0.4
+		l, _, errL := cssComponent(args[0], 1)
+		x, _, errX := cssComponent(args[1], 0.4)
+		if err := errors.Join(errL, errX); err != nil {
+			return nil, fmt.Errorf("%s(): %w", name, err)
+		}
+		if name == "oklab" {
+			y, _, err := cssComponent(args[2], 0.4)
+			if err != nil {
+				return nil, fmt.Errorf("%s(): %w", name, err)
+			}
+			c = Oklab{l, x, y}.ToOklch().fitGamut()
+		} else {
+			h, err := cssHue(args[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s(): %w", name, err)
+			}
+			c = Oklch{l, max(x, 0), h}.fitGamut()
+		}
+	default:
+		return nil, fmt.Errorf("unknown color function %s()", name)
+	}
+
+	if len(args) == 3 {
+		return c, nil
+	}
+	a, err := cssAlpha(args[3])
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", name, err)
+	}
+	return c.WithAlpha(a), nil
+}
+
+var (
+	cssComment  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssVariable = regexp.MustCompile(`(--[\w-]+)\s*:\s*([^;{}]+)`)
+)
+
+// ParseCSSVariables scans CSS for custom property declarations like
+//
+//	:root { --primary: #336699; }
+//
+// and returns the colors they hold keyed by property name, dashes included.
+// Values Parse doesn't accept are taken to be something other than a color
+// and skipped, as are bare numbers like "100", which CSS never reads as hex
+// without a #. The only errors returned are from reading r.
+func ParseCSSVariables(r io.Reader) (map[string]color.Color, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src = cssComment.ReplaceAll(src, nil)
+
+	out := map[string]color.Color{}
+	for _, m := range cssVariable.FindAllSubmatch(src, -1) {
+		value := strings.TrimSpace(string(m[2]))
+		if value == "" || isHex(value) {
+			continue
+		}
+		if c, err := Parse(value); err == nil {
+			out[string(m[1])] = c
+		}
+	}
+	return out, nil
+}
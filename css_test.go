@@ -0,0 +1,227 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestParseKeywords(t *testing.T) {
+	for _, s := range []string{"transparent", "TRANSPARENT", " transparent "} {
+		c, err := Parse(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if r, g, b, a := c.RGBA(); r|g|b|a != 0 {
+			t.Errorf("%q: have %d %d %d %d, want all 0", s, r, g, b, a)
+		}
+	}
+	for _, s := range []string{"currentColor", "currentcolor"} {
+		if c, err := Parse(s); !errors.Is(err, ErrCurrentColor) {
+			t.Errorf("%q: have %v %v, want %v", s, c, err, ErrCurrentColor)
+		}
+	}
+	if c, err := Parse("#ff0000"); err != nil || c != (RGB{1, 0, 0}) {
+		t.Errorf("#ff0000: have %v %v, want %v", c, err, RGB{1, 0, 0})
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want RGB
+	}{
+		{"red", RGB{1, 0, 0}},
+		{" RebeccaPurple ", FromHexInt(0x663399)},
+		{"blue", RGB{0, 0, 1}},
+		{"seashell", FromHexInt(0xfff5ee)},
+	} {
+		if have, err := Parse(test.in); err != nil || have != test.want {
+			t.Errorf("%q: have %v %v, want %v", test.in, have, err, test.want)
+		}
+	}
+	for _, in := range []string{"notacolor", "#red", "#blue"} {
+		if c, err := Parse(in); err == nil {
+			t.Errorf("%q: have %v, want an error", in, c)
+		}
+	}
+}
+
+func TestParseCSSVariables(t *testing.T) {
+	const src = `
+:root {
+	--primary: #336699;
+	--accent:var(--primary) ;
+	--spacing: 4px;
+	--font-weight: bold;
+	/* --commented: #000000; */
+	--text-color: #ffffff;
+	--current: currentColor;
+	--z-index: 100;
+	--font-weight: 600;
+	--line: 150;
+	--opacity: 0.5;
+	--bare: ffffff;
+	--brand: rebeccapurple;
+}
+.card { --card-bg: transparent; color: #123456 }
+`
+	vars, err := ParseCSSVariables(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]color.Color{
+		"--primary":    RGB{0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0},
+		"--text-color": RGB{1, 1, 1},
+		"--card-bg":    RGBA{},
+		"--brand":      FromHexInt(0x663399),
+	}
+	if len(vars) != len(want) {
+		t.Errorf("have %d variables, want %d: %v", len(vars), len(want), vars)
+	}
+	for name, w := range want {
+		if have, ok := vars[name]; !ok || have != w {
+			t.Errorf("%s: have %v, want %v", name, have, w)
+		}
+	}
+}
+
+func TestParseOklab(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want color.Color
+	}{
+		{"oklch(0% 0 0)", black},
+		{"oklch(0 0 0)", black},
+		{"OKLCH(100% 0 0)", white},
+		{"oklab(0% 0 0)", black},
+		{"oklab(1 0 0)", white},
+		{"oklch(0% 0 0 / 50%)", RGBA{0, 0, 0, 0.5}},
+		{"oklab(0 0 0 / 0.25)", RGBA{0, 0, 0, 0.25}},
+	} {
+		have, err := Parse(test.in)
+		if err != nil {
+			t.Errorf("%s: %v", test.in, err)
+			continue
+		}
+		if !eqColor(have, test.want) {
+			t.Errorf("%s: have %v, want %v", test.in, have, test.want)
+		}
+	}
+
+	// a mid value survives the trip through its CSS form
+	want := RGB{0.2, 0.5, 0.7}
+	lch := want.ToOklch()
+	for _, in := range []string{
+		fmt.Sprintf("oklch(%f%% %f %fdeg)", lch.L*100, lch.C, lch.H*360),
+		fmt.Sprintf("oklch(%f %f%% %fturn)", lch.L, lch.C/0.4*100, lch.H),
+		fmt.Sprintf("oklab(%f %f %f)", lch.L, lch.ToOklab().A, lch.ToOklab().B),
+	} {
+		have, err := Parse(in)
+		if err != nil {
+			t.Errorf("%s: %v", in, err)
+		} else if rgb, ok := have.(RGB); !ok || DistanceCIEDE2000(rgb, want) > 0.01 {
+			t.Errorf("%s: have %v, want %v", in, have, want)
+		}
+	}
+
+	// out of gamut colors are pulled in at the same lightness and hue
+	if have, err := Parse("oklch(60% 0.4 150)"); err != nil {
+		t.Errorf("vivid green: %v", err)
+	} else if rgb := have.(RGB); !rgb.inGamut() || hueDistance(rgb.ToOklch().H, 150.0/360) > 0.01 {
+		t.Errorf("vivid green: have %v", rgb.ToOklch())
+	}
+
+	for _, in := range []string{"oklch(50% 0.1)", "oklch(50% 0.1 20 / 1 / 1)", "oklch(50% x 20)", "oklab(1, 0, 0 / 1)", "hwb(0 0% 0%)"} {
+		if c, err := Parse(in); err == nil {
+			t.Errorf("%s: have %v, want an error", in, c)
+		}
+	}
+}
+
+func TestParseCSSRGB(t *testing.T) {
+	pink := RGB{1, 0, 128 / 255.0}
+	for _, test := range []struct {
+		in   string
+		want color.Color
+	}{
+		{"rgb(255, 0, 128)", pink},
+		{"rgb(255,0,128)", pink},
+		{"  RGB( 255 ,  0 , 128 )  ", pink},
+		{"rgb(255 0 128)", pink},
+		{"rgb(100%, 0%, 50%)", RGB{1, 0, 0.5}},
+		{"rgb(300, -20, 0)", RGB{1, 0, 0}},
+		{"rgba(255,0,128,0.5)", pink.WithAlpha(0.5)},
+		{"rgba(255, 0, 128, 50%)", pink.WithAlpha(0.5)},
+		{"rgb(255 0 128 / 0.25)", pink.WithAlpha(0.25)},
+		{"rgba(0, 0, 0, 0)", RGBA{}},
+	} {
+		have, err := ParseCSS(test.in)
+		if err != nil {
+			t.Errorf("%q: %v", test.in, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("%q: have %v, want %v", test.in, have, test.want)
+		}
+		if p, err := Parse(test.in); err != nil || p != have {
+			t.Errorf("%q: Parse has %v %v, want %v", test.in, p, err, have)
+		}
+	}
+
+	for _, in := range []string{
+		"rgb(255, 0%, 128)",
+		"rgb(255, 0, 128, 0.5, 1)",
+		"rgb(255, 0)",
+		"rgb(255, , 128)",
+		"rgb(red, 0, 0)",
+		"rgba(255, 0, 128, x)",
+		"rgb 255, 0, 128",
+		"#ff0080",
+	} {
+		if c, err := ParseCSS(in); err == nil {
+			t.Errorf("%q: have %v, want an error", in, c)
+		}
+	}
+}
+
+func TestParseCSSHSL(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want color.Color
+	}{
+		{"hsl(120, 50%, 50%)", HSL{1 / 3.0, 0.5, 0.5}},
+		{"HSL( 120 ,50% , 50% )", HSL{1 / 3.0, 0.5, 0.5}},
+		{"hsl(120deg 50% 50%)", HSL{1 / 3.0, 0.5, 0.5}},
+		{"hsl(480, 50%, 50%)", HSL{1 / 3.0, 0.5, 0.5}},
+		{"hsl(-240, 50%, 50%)", HSL{1 / 3.0, 0.5, 0.5}},
+		{"hsl(0.5turn, 100%, 25%)", HSL{0.5, 1, 0.25}},
+		{"hsl(0, 150%, -10%)", HSL{0, 1, 0}},
+		{"hsla(120, 50%, 50%, 0.3)", HSL{1 / 3.0, 0.5, 0.5}.ToRGB().WithAlpha(0.3)},
+		{"hsl(120 50% 50% / 30%)", HSL{1 / 3.0, 0.5, 0.5}.ToRGB().WithAlpha(0.3)},
+	} {
+		have, err := ParseCSS(test.in)
+		if err != nil {
+			t.Errorf("%q: %v", test.in, err)
+			continue
+		}
+		if hsl, ok := test.want.(HSL); ok {
+			h, ok := have.(HSL)
+			if !ok || real.Diff(h.H, hsl.H) > epsilonF || h.S != hsl.S || h.L != hsl.L {
+				t.Errorf("%q: have %v, want %v", test.in, have, test.want)
+			}
+		} else if !eqColor(have, test.want) {
+			t.Errorf("%q: have %v, want %v", test.in, have, test.want)
+		}
+	}
+
+	for _, in := range []string{"hsl(120, 50, 50%)", "hsl(120, 50%, 0.5)", "hsl(green, 50%, 50%)", "hsl(120, 50%)"} {
+		if c, err := ParseCSS(in); err == nil {
+			t.Errorf("%q: have %v, want an error", in, c)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package color
+
+import "math"
+
+// DistanceFunc measures how different two colors are, with 0 for identical colors
+type DistanceFunc func(a, b RGB) float64
+
+// DistanceRGB returns the Euclidean distance between a and b in the RGB cube,
+// in [0, √3]. It is cheap but only loosely tracks how different colors look.
+func DistanceRGB(a, b RGB) float64 {
+	return math.Sqrt((a.R-b.R)*(a.R-b.R) + (a.G-b.G)*(a.G-b.G) + (a.B-b.B)*(a.B-b.B))
+}
+
+// DistanceCIEDE2000 returns the CIEDE2000 color difference (ΔE₀₀) between a and b.
+// A difference under 1 is generally imperceptible.
+func DistanceCIEDE2000(a, b RGB) float64 {
+	return ciede2000(a.ToLab(), b.ToLab())
+}
+
+// DeltaECategory describes how different a and b look, going by their
+// CIEDE2000 difference: "identical" under 1, "barely perceptible" under 2,
+// "perceptible" under 10, "different" up to 50, and "very different" beyond
+func DeltaECategory(a, b RGB) string {
+	return deltaECategory(DistanceCIEDE2000(a, b))
+}
+
+func deltaECategory(d float64) string {
+	switch {
+	case d < 1:
+		return "identical"
+	case d < 2:
+		return "barely perceptible"
+	case d < 10:
+		return "perceptible"
+	case d <= 50:
+		return "different"
+	default:
+		return "very different"
+	}
+}
+
+// rad converts degrees to radians
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// hueDeg returns the angle of (a, b) in degrees in [0, 360)
+func hueDeg(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// ciede2000 implements the CIEDE2000 formula as given by Sharma, Wu, and Dalal (2005),
+// with unit weighting factors
+func ciede2000(x, y Lab) float64 {
+	pow7 := math.Pow(25, 7)
+
+	cBar := (math.Hypot(x.A, x.B) + math.Hypot(y.A, y.B)) / 2
+	cBar7 := math.Pow(cBar, 7)
+	g := 0.5 * (1 - math.Sqrt(cBar7/(cBar7+pow7)))
+	a1, a2 := (1+g)*x.A, (1+g)*y.A
+	c1, c2 := math.Hypot(a1, x.B), math.Hypot(a2, y.B)
+	h1, h2 := hueDeg(x.B, a1), hueDeg(y.B, a2)
+
+	dL := y.L - x.L
+	dC := c2 - c1
+	var dh float64
+	switch {
+	case c1*c2 == 0:
+		dh = 0
+	case math.Abs(h2-h1) <= 180:
+		dh = h2 - h1
+	case h2-h1 > 180:
+		dh = h2 - h1 - 360
+	default:
+		dh = h2 - h1 + 360
+	}
+	dH := 2 * math.Sqrt(c1*c2) * math.Sin(rad(dh/2))
+
+	lBar := (x.L + y.L) / 2
+	cBar = (c1 + c2) / 2
+	var hBar float64
+	switch {
+	case c1*c2 == 0:
+		hBar = h1 + h2
+	case math.Abs(h1-h2) <= 180:
+		hBar = (h1 + h2) / 2
+	case h1+h2 < 360:
+		hBar = (h1 + h2 + 360) / 2
+	default:
+		hBar = (h1 + h2 - 360) / 2
+	}
+
+	t := 1 - 0.17*math.Cos(rad(hBar-30)) + 0.24*math.Cos(rad(2*hBar)) +
+		0.32*math.Cos(rad(3*hBar+6)) - 0.20*math.Cos(rad(4*hBar-63))
+	dTheta := 30 * math.Exp(-math.Pow((hBar-275)/25, 2))
+	cBar7 = math.Pow(cBar, 7)
+	rC := 2 * math.Sqrt(cBar7/(cBar7+pow7))
+	l50 := (lBar - 50) * (lBar - 50)
+	sL := 1 + 0.015*l50/math.Sqrt(20+l50)
+	sC := 1 + 0.045*cBar
+	sH := 1 + 0.015*cBar*t
+	rT := -math.Sin(rad(2*dTheta)) * rC
+
+	dL, dC, dH = dL/sL, dC/sC, dH/sH
+	return math.Sqrt(dL*dL + dC*dC + dH*dH + rT*dC*dH)
+}
+
+// Cluster greedily groups colors that lie within threshold CIEDE2000 of each other.
+// Each color joins the first group whose founding color is close enough,
+// or else founds a new group, so the result depends on the order of colors.
+func Cluster(colors []RGB, threshold float64) [][]RGB {
+	var groups [][]RGB
+	var seeds []Lab
+next:
+	for _, c := range colors {
+		lab := c.ToLab()
+		for i, seed := range seeds {
+			if ciede2000(seed, lab) <= threshold {
+				groups[i] = append(groups[i], c)
+				continue next
+			}
+		}
+		seeds = append(seeds, lab)
+		groups = append(groups, []RGB{c})
+	}
+	return groups
+}
+
+// hueDistance returns the distance between two hues in [0, 1] around the wheel, in [0, 0.5]
+func hueDistance(a, b float64) float64 {
+	d := math.Abs(wrapHue(a) - wrapHue(b))
+	return min(d, 1-d)
+}
+
+// ChromaticMatch reports whether a and b have the same hue and saturation,
+// to within tolerance on the [0, 1] scales of HSL, regardless of their lightness.
+// Colors too close to gray to have a stable hue only match each other.
+func ChromaticMatch(a, b RGB, tolerance float64) bool {
+	ha, oka := a.StableHue()
+	hb, okb := b.StableHue()
+	if !oka || !okb {
+		return oka == okb
+	}
+	return hueDistance(ha, hb) <= tolerance && math.Abs(a.ToHSL().S-b.ToHSL().S) <= tolerance
+}
+
+// Nearest returns the color in palette nearest to target by CIEDE2000, and its index.
+// It returns the zero RGB and -1 for an empty palette.
+func Nearest(target RGB, palette []RGB) (RGB, int) {
+	return NearestBy(target, palette, DistanceCIEDE2000)
+}
+
+// NearestBy returns the color in palette nearest to target by dist, and its
+// index, taking the first of any ties. It returns the zero RGB and -1 for an
+// empty palette.
+func NearestBy(target RGB, palette []RGB, dist DistanceFunc) (RGB, int) {
+	best, bestD := -1, math.Inf(1)
+	for i, c := range palette {
+		if d := dist(target, c); d < bestD {
+			best, bestD = i, d
+		}
+	}
+	if best < 0 {
+		return RGB{}, -1
+	}
+	return palette[best], best
+}
@@ -0,0 +1,160 @@
+package color
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestCIEDE2000(t *testing.T) {
+	// a sample of the test data published alongside Sharma, Wu, and Dalal (2005)
+	for i, test := range []struct {
+		x, y Lab
+		want float64
+	}{
+		{Lab{50, 2.6772, -79.7751}, Lab{50, 0, -82.7485}, 2.0425},
+		{Lab{50, 3.1571, -77.2803}, Lab{50, 0, -82.7485}, 2.8615},
+		{Lab{50, -1.3802, -84.2814}, Lab{50, 0, -82.7485}, 1.0000},
+		{Lab{50, 0, 0}, Lab{50, -1, 2}, 2.3669},
+		{Lab{50, 2.49, -0.001}, Lab{50, -2.49, 0.0009}, 7.1792},
+		{Lab{50, 2.5, 0}, Lab{73, 25, -18}, 27.1492},
+		{Lab{60.2574, -34.0099, 36.2677}, Lab{60.4626, -34.1751, 39.4387}, 1.2644},
+		{Lab{22.7233, 20.0904, -46.694}, Lab{23.0331, 14.973, -42.5619}, 2.0373},
+		{Lab{90.9257, -0.5406, -0.9208}, Lab{88.6381, -0.8985, -0.7239}, 1.5381},
+	} {
+		if have := ciede2000(test.x, test.y); real.Diff(have, test.want) > 1e-4 {
+			t.Errorf("%d: have %.4f, want %.4f", i, have, test.want)
+		}
+		if have := ciede2000(test.y, test.x); real.Diff(have, test.want) > 1e-4 {
+			t.Errorf("%d reversed: have %.4f, want %.4f", i, have, test.want)
+		}
+	}
+}
+
+func TestDeltaECategory(t *testing.T) {
+	for _, test := range []struct {
+		d    float64
+		want string
+	}{
+		{0, "identical"},
+		{0.99, "identical"},
+		{1, "barely perceptible"},
+		{1.5, "barely perceptible"},
+		{2, "perceptible"},
+		{9.99, "perceptible"},
+		{10, "different"},
+		{50, "different"},
+		{50.01, "very different"},
+		{100, "very different"},
+	} {
+		if have := deltaECategory(test.d); have != test.want {
+			t.Errorf("%v: have %q, want %q", test.d, have, test.want)
+		}
+	}
+
+	gray := RGB{0.5, 0.5, 0.5}
+	for _, test := range []struct {
+		a, b RGB
+		want string
+	}{
+		{gray, gray, "identical"},
+		{gray, RGB{0.5, 0.5, 0.502}, "identical"},
+		{gray, RGB{0.52, 0.5, 0.5}, "perceptible"},
+		{RGB{1, 0, 0}, RGB{1, 0.5, 0}, "different"},
+		{black, white, "very different"},
+	} {
+		if have := DeltaECategory(test.a, test.b); have != test.want {
+			t.Errorf("%v %v (ΔE %.2f): have %q, want %q", test.a, test.b, DistanceCIEDE2000(test.a, test.b), have, test.want)
+		}
+	}
+}
+
+func TestCluster(t *testing.T) {
+	reds := []RGB{{0.9, 0.1, 0.1}, {0.92, 0.12, 0.1}, {0.88, 0.1, 0.12}}
+	blues := []RGB{{0.1, 0.2, 0.8}, {0.12, 0.2, 0.82}}
+	colors := []RGB{reds[0], blues[0], reds[1], blues[1], reds[2]}
+
+	groups := Cluster(colors, 5)
+	if len(groups) != 2 {
+		t.Fatalf("have %d groups, want 2: %v", len(groups), groups)
+	}
+	for i, want := range [][]RGB{reds, blues} {
+		if len(groups[i]) != len(want) {
+			t.Errorf("group %d: have %v, want %v", i, groups[i], want)
+			continue
+		}
+		for j := range want {
+			if groups[i][j] != want[j] {
+				t.Errorf("group %d: have %v, want %v", i, groups[i], want)
+				break
+			}
+		}
+	}
+
+	if have := Cluster(colors, 0); len(have) != len(colors) {
+		t.Errorf("zero threshold: have %d groups, want %d", len(have), len(colors))
+	}
+}
+
+func TestChromaticMatch(t *testing.T) {
+	const tolerance = 0.02
+	for _, test := range []struct {
+		a, b HSL
+		want bool
+	}{
+		{HSL{0.6, 0.8, 0.3}, HSL{0.6, 0.8, 0.7}, true},
+		{HSL{0.99, 0.5, 0.2}, HSL{0.005, 0.5, 0.8}, true},
+		{HSL{0.6, 0.8, 0.3}, HSL{0.1, 0.8, 0.3}, false},
+		{HSL{0.6, 0.8, 0.5}, HSL{0.6, 0.3, 0.5}, false},
+		{HSL{0, 0, 0.2}, HSL{0, 0, 0.9}, true},
+		{HSL{0, 0, 0.5}, HSL{0.6, 0.8, 0.5}, false},
+	} {
+		if have := ChromaticMatch(test.a.ToRGB(), test.b.ToRGB(), tolerance); have != test.want {
+			t.Errorf("%v, %v: have %v, want %v", test.a, test.b, have, test.want)
+		}
+	}
+}
+
+func TestDistanceRGB(t *testing.T) {
+	if have := DistanceRGB(black, white); real.Diff(have, math.Sqrt(3)) > epsilonF {
+		t.Errorf("black to white: have %f, want √3", have)
+	}
+	a, b := RGB{0.4, 0.5, 0.6}, RGB{0.401, 0.5, 0.6}
+	if have := DistanceRGB(a, b); real.Diff(have, 0.001) > epsilonF {
+		t.Errorf("near: have %f, want 0.001", have)
+	}
+	if have := DistanceCIEDE2000(a, b); have > 0.1 {
+		t.Errorf("near: have ΔE %f, want near 0", have)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	palette := []RGB{{1, 0, 0}, {0, 0.5, 0}, {0, 0, 1}, {1, 1, 1}}
+	for _, test := range []struct {
+		target RGB
+		want   int
+	}{
+		{RGB{0.9, 0.1, 0.1}, 0},
+		{RGB{0.1, 0.6, 0.2}, 1},
+		{RGB{0.2, 0.2, 0.7}, 2},
+		{RGB{0.95, 0.95, 0.9}, 3},
+	} {
+		if c, i := Nearest(test.target, palette); i != test.want || c != palette[test.want] {
+			t.Errorf("%s: have %d %v, want %d", test.target.ToHTML(), i, c, test.want)
+		}
+	}
+
+	// any metric can be plugged in, here one blind to everything but luminance
+	byLuminance := func(a, b RGB) float64 { return math.Abs(a.Luminance() - b.Luminance()) }
+	if _, i := NearestBy(RGB{1, 1, 0}, palette, byLuminance); i != 3 {
+		t.Errorf("yellow by luminance: have %d, want 3", i)
+	}
+	if _, i := NearestBy(black, []RGB{{0, 1, 0}, white, {0, 1, 0}}, byLuminance); i != 0 {
+		t.Errorf("ties: have %d, want the first", i)
+	}
+
+	if c, i := Nearest(white, nil); i != -1 || c != (RGB{}) {
+		t.Errorf("empty: have %d %v, want -1", i, c)
+	}
+}
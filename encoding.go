@@ -0,0 +1,79 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// parseHexOrName reads a hex color as accepted by HTMLToRGB or a CSS color name
+func parseHexOrName(s string) (RGB, error) {
+	if c, err := HTMLToRGB(s); err == nil {
+		return c, nil
+	}
+	if c, err := ByName(s); err == nil {
+		return c, nil
+	}
+	return RGB{}, fmt.Errorf("invalid color %q: want a hex code like #336699 or a CSS color name", s)
+}
+
+// MarshalJSON encodes c as a hex string like "#336699"
+func (c RGB) MarshalJSON() ([]byte, error) {
+	return json.Marshal("#" + c.ToHTML())
+}
+
+// UnmarshalJSON decodes c from a hex string, with or without the leading #,
+// or from a CSS color name
+func (c *RGB) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid color %s: want a string", data)
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// MarshalJSON encodes c as the hex string of its RGB equivalent, see RGB.MarshalJSON
+func (c HSL) MarshalJSON() ([]byte, error) {
+	return c.ToRGB().MarshalJSON()
+}
+
+// UnmarshalJSON decodes c as RGB.UnmarshalJSON does, converting to HSL
+func (c *HSL) UnmarshalJSON(data []byte) error {
+	var rgb RGB
+	if err := rgb.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*c = rgb.ToHSL()
+	return nil
+}
+
+// MarshalText encodes c as hex text like #336699, for use with flag, env, and
+// YAML libraries
+func (c RGB) MarshalText() ([]byte, error) {
+	return []byte("#" + c.ToHTML()), nil
+}
+
+// UnmarshalText decodes c from hex text, with or without the leading #,
+// or from a CSS color name
+func (c *RGB) UnmarshalText(text []byte) error {
+	rgb, err := parseHexOrName(string(text))
+	if err != nil {
+		return err
+	}
+	*c = rgb
+	return nil
+}
+
+// MarshalText encodes c as the hex text of its RGB equivalent, see RGB.MarshalText
+func (c HSL) MarshalText() ([]byte, error) {
+	return c.ToRGB().MarshalText()
+}
+
+// UnmarshalText decodes c as RGB.UnmarshalText does, converting to HSL
+func (c *HSL) UnmarshalText(text []byte) error {
+	var rgb RGB
+	if err := rgb.UnmarshalText(text); err != nil {
+		return err
+	}
+	*c = rgb.ToHSL()
+	return nil
+}
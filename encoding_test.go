@@ -0,0 +1,98 @@
+package color
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = RGB{}
+	_ encoding.TextUnmarshaler = &RGB{}
+	_ encoding.TextMarshaler   = HSL{}
+	_ encoding.TextUnmarshaler = &HSL{}
+	_ json.Marshaler           = RGB{}
+	_ json.Unmarshaler         = &RGB{}
+	_ json.Marshaler           = HSL{}
+	_ json.Unmarshaler         = &HSL{}
+)
+
+func TestJSON(t *testing.T) {
+	type config struct {
+		Fg RGB `json:"fg"`
+		Bg HSL `json:"bg"`
+	}
+	in := config{RGB{0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}, RGB{1, 0x80 / 255.0, 0}.ToHSL()}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(data), `{"fg":"#336699","bg":"#ff8000"}`; have != want {
+		t.Errorf("marshal: have %s, want %s", have, want)
+	}
+
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Fg != in.Fg || out.Bg.ToHTML() != in.Bg.ToHTML() {
+		t.Errorf("round trip: have %v, want %v", out, in)
+	}
+
+	for _, test := range []struct {
+		in, want string
+	}{
+		{`"#336699"`, "336699"},
+		{`"336699"`, "336699"},
+		{`"#abc"`, "aabbcc"},
+		{`"RebeccaPurple"`, "663399"},
+	} {
+		var c RGB
+		if err := json.Unmarshal([]byte(test.in), &c); err != nil || c.ToHTML() != test.want {
+			t.Errorf("%s: have %s %v, want %s", test.in, c.ToHTML(), err, test.want)
+		}
+	}
+	for _, in := range []string{`""`, `"#12345"`, `"notacolor"`, `123`, `[0.2, 0.4, 0.6]`, `{"R": 1}`} {
+		var c RGB
+		if err := json.Unmarshal([]byte(in), &c); err == nil {
+			t.Errorf("%s: have %v, want an error", in, c)
+		}
+		var h HSL
+		if err := json.Unmarshal([]byte(in), &h); err == nil {
+			t.Errorf("%s as HSL: have %v, want an error", in, h)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	c := RGB{0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}
+	for _, m := range []encoding.TextMarshaler{c, c.ToHSL()} {
+		if text, err := m.MarshalText(); err != nil || string(text) != "#336699" {
+			t.Errorf("%v: have %s %v, want #336699", m, text, err)
+		}
+	}
+
+	for _, in := range []string{"#336699", "336699", " #336699 "} {
+		var have RGB
+		if err := have.UnmarshalText([]byte(in)); err != nil || have != c {
+			t.Errorf("%q: have %v %v, want %v", in, have, err, c)
+		}
+		var hsl HSL
+		if err := hsl.UnmarshalText([]byte(in)); err != nil || hsl.ToHTML() != "336699" {
+			t.Errorf("%q as HSL: have %v %v, want %v", in, hsl, err, c.ToHSL())
+		}
+	}
+	var bad RGB
+	if err := bad.UnmarshalText([]byte("#33669")); err == nil {
+		t.Errorf("bad text: have %v, want an error", bad)
+	}
+
+	// as a flag
+	var fg RGB
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(&fg, "fg", RGB{}, "foreground color")
+	if err := fs.Parse([]string{"-fg", "#336699"}); err != nil || fg != c {
+		t.Errorf("flag: have %v %v, want %v", fg, err, c)
+	}
+}
@@ -0,0 +1,104 @@
+package color
+
+// A tolerance for rounding error when testing gamut boundaries
+const gamutEpsilon = 1e-6
+
+// Matrices taking CIE XYZ to the linear RGB of wide gamut display spaces,
+// derived from their primaries and the same D65 white as the sRGB conversions
+var (
+	xyzToP3 = [3][3]float64{
+		{2.4931807553, -0.9312655255, -0.4026597238},
+		{-0.8295031158, 1.7626941211, 0.0236250887},
+		{0.0358536258, -0.0761889548, 0.9570926215},
+	}
+	xyzToRec2020 = [3][3]float64{
+		{1.7165106698, -0.3556416700, -0.2533455418},
+		{-0.6666930012, 1.6165022083, 0.0157687504},
+		{0.0176436388, -0.0427797817, 0.9423050727},
+	}
+	p3ToXYZ = [3][3]float64{
+		{0.4866326500, 0.2656631625, 0.1981741875},
+		{0.2290036000, 0.6917267250, 0.0792696750},
+		{0, 0.0451126125, 1.0437173875},
+	}
+	rec2020ToXYZ = [3][3]float64{
+		{0.6370101914, 0.1446150274, 0.1688447812},
+		{0.2627217174, 0.6779892755, 0.0592890071},
+		{0, 0.0280723289, 1.0607576712},
+	}
+)
+
+// inUnitCube reports whether each of vs lies in [0, 1], give or take rounding error
+func inUnitCube(vs ...float64) bool {
+	for _, v := range vs {
+		if v < -gamutEpsilon || v > 1+gamutEpsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// IsWithinGamutOf reports whether c can be represented in the named RGB space.
+// The spaces known are "srgb", "display-p3" (or "p3"), "rec2020" (or "bt2020"),
+// and "acescg", with names matched as leniently as color names.
+// Unknown spaces report false.
+//
+// Every valid RGB fits in sRGB and the wider spaces, so this is mostly of use for
+// the out of range values that arithmetic and conversions from Lab can produce.
+func (c RGB) IsWithinGamutOf(space string) bool {
+	var m [3][3]float64
+	switch normalizeName(space) {
+	case "srgb":
+		return inUnitCube(c.R, c.G, c.B)
+	case "acescg":
+		a := c.ToACEScg()
+		return inUnitCube(a.R, a.G, a.B)
+	case "displayp3", "p3":
+		m = xyzToP3
+	case "rec2020", "bt2020":
+		m = xyzToRec2020
+	default:
+		return false
+	}
+	x := c.ToXYZ()
+	r, g, b := mul3(m, x.X, x.Y, x.Z)
+	return inUnitCube(r, g, b)
+}
+
+// clipVia converts c into the linear RGB space reached from XYZ by to, clamps
+// it there, and brings it back through from
+func clipVia(c RGB, to, from [3][3]float64) RGB {
+	x := c.ToXYZ()
+	r, g, b := mul3(to, x.X, x.Y, x.Z)
+	X, Y, Z := mul3(from, clamp01(r), clamp01(g), clamp01(b))
+	return XYZ{X, Y, Z}.ToRGB()
+}
+
+// WouldClipIn reports whether converting c into the named RGB space, as known
+// to IsWithinGamutOf, and back again would change it, along with the color it
+// would come back as. Colors within the space's gamut, as IsWithinGamutOf
+// judges it, come back exactly as c.
+// Unknown spaces report false.
+//
+// As with IsWithinGamutOf, only out of range values, such as the extended sRGB
+// of a color from a wider space, can clip in the wide gamut spaces.
+func (c RGB) WouldClipIn(space string) (bool, RGB) {
+	if c.IsWithinGamutOf(space) {
+		return false, c
+	}
+	var clipped RGB
+	switch normalizeName(space) {
+	case "srgb":
+		clipped = c.Clamp()
+	case "acescg":
+		a := c.ToACEScg()
+		clipped = ACEScg{clamp01(a.R), clamp01(a.G), clamp01(a.B)}.ToRGB()
+	case "displayp3", "p3":
+		clipped = clipVia(c, xyzToP3, p3ToXYZ)
+	case "rec2020", "bt2020":
+		clipped = clipVia(c, xyzToRec2020, rec2020ToXYZ)
+	default:
+		return false, c
+	}
+	return true, clipped
+}
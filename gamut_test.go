@@ -0,0 +1,64 @@
+package color
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIsWithinGamutOf(t *testing.T) {
+	spaces := []string{"srgb", "sRGB", "display-p3", "p3", "rec2020", "BT2020", "acescg"}
+	colors := []RGB{black, white, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 0}, {0, 1, 1}, {1, 0, 1}}
+	for range nTrials {
+		colors = append(colors, RGB{rand.Float64(), rand.Float64(), rand.Float64()})
+	}
+	for _, c := range colors {
+		for _, space := range spaces {
+			if !c.IsWithinGamutOf(space) {
+				t.Errorf("%s doesn't fit %s", c.ToHTML(), space)
+			}
+		}
+	}
+
+	// redder than sRGB red, as Lab arithmetic can produce
+	beyond := RGB{1.1, -0.05, 0}
+	if beyond.IsWithinGamutOf("srgb") {
+		t.Errorf("%v fits srgb", beyond)
+	}
+	if !beyond.IsWithinGamutOf("rec2020") {
+		t.Errorf("%v doesn't fit rec2020", beyond)
+	}
+	if white.IsWithinGamutOf("cmyk-ish") {
+		t.Errorf("white fits an unknown space")
+	}
+}
+
+func TestWouldClipIn(t *testing.T) {
+	for _, c := range []RGB{black, white, {1, 0, 0}, {0.2, 0.5, 0.8}} {
+		for _, space := range []string{"srgb", "p3", "rec2020", "acescg"} {
+			if clips, have := c.WouldClipIn(space); clips || have != c {
+				t.Errorf("%s in %s: clips to %v", c.ToHTML(), space, have)
+			}
+		}
+	}
+
+	// Rec. 2020's green primary as extended sRGB, beyond both sRGB and P3
+	x, y, z := mul3(rec2020ToXYZ, 0, 1, 0)
+	green := XYZ{x, y, z}.ToRGB()
+	if clips, _ := green.WouldClipIn("rec2020"); clips {
+		t.Errorf("%v clips in rec2020", green)
+	}
+	for _, space := range []string{"srgb", "display-p3"} {
+		clips, have := green.WouldClipIn(space)
+		if !clips {
+			t.Errorf("%v doesn't clip in %s", green, space)
+			continue
+		}
+		if !have.IsWithinGamutOf(space) {
+			t.Errorf("%s: clipped to %v, still outside", space, have)
+		}
+	}
+
+	if clips, have := green.WouldClipIn("cmyk-ish"); clips || have != green {
+		t.Errorf("unknown space: have %v %v", clips, have)
+	}
+}
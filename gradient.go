@@ -0,0 +1,145 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GradientAt returns the color at position t along the piecewise linear gradient
+// through stops, which are spaced evenly over [0, 1]. t is clamped into [0, 1].
+// It returns the zero RGB when there are no stops.
+func GradientAt(stops []RGB, t float64) RGB {
+	switch len(stops) {
+	case 0:
+		return RGB{}
+	case 1:
+		return stops[0]
+	}
+	pos := clamp01(t) * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	return Mix(stops[i], stops[i+1], pos-float64(i))
+}
+
+// Gradient returns n evenly spaced colors from a to b inclusive, mixed as in
+// Mix, with the first exactly a and the last exactly b. It returns nil for n < 2.
+func Gradient(a, b RGB, n int) []RGB {
+	return GradientStops([]RGB{a, b}, n)
+}
+
+// GradientStops returns n evenly spaced colors along the gradient through
+// stops, as given by GradientAt, starting and ending exactly on the first and
+// last stops. It returns nil for n < 2 or no stops.
+func GradientStops(stops []RGB, n int) []RGB {
+	if n < 2 || len(stops) == 0 {
+		return nil
+	}
+	out := make([]RGB, n)
+	for i := range out {
+		out[i] = GradientAt(stops, float64(i)/float64(n-1))
+	}
+	return out
+}
+
+// CyclicGradient returns n colors sampled evenly around the closed loop
+// through stops, where the last stop runs back into the first, for animations
+// that repeat seamlessly. The first color is the first stop and the color that
+// would follow the last is the first again. Colors are mixed as in GradientAt.
+func CyclicGradient(stops []RGB, n int) []RGB {
+	if n <= 0 || len(stops) == 0 {
+		return nil
+	}
+	out := make([]RGB, n)
+	for i := range out {
+		pos := float64(i) * float64(len(stops)) / float64(n)
+		j := int(pos)
+		out[i] = Mix(stops[j], stops[(j+1)%len(stops)], pos-float64(j))
+	}
+	return out
+}
+
+// GradientPerceptualLength returns the length of the path through stops as
+// the eye sees it, the sum of the CIEDE2000 differences between consecutive
+// stops. Dividing it evenly is a way to place stops by look rather than index.
+// It is 0 for fewer than two stops.
+func GradientPerceptualLength(stops []RGB) float64 {
+	length := 0.0
+	for i := 1; i < len(stops); i++ {
+		length += DistanceCIEDE2000(stops[i-1], stops[i])
+	}
+	return length
+}
+
+// cssNumber formats v for CSS output, rounded to 4 decimal places
+func cssNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// CSSLinearGradient renders stops as a CSS linear-gradient() running at the given
+// angle in degrees, with the stops spread evenly, as in
+//
+//	linear-gradient(90deg, #ff0000 0%, #0000ff 100%)
+func CSSLinearGradient(stops []RGB, angle float64) string {
+	var sb strings.Builder
+	sb.WriteString("linear-gradient(")
+	sb.WriteString(cssNumber(angle))
+	sb.WriteString("deg")
+	for i, c := range stops {
+		var pos float64
+		if len(stops) > 1 {
+			pos = 100 * float64(i) / float64(len(stops)-1)
+		}
+		fmt.Fprintf(&sb, ", #%s %s%%", c.ToHTML(), cssNumber(pos))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// GradientToComplement returns n colors running from c to its complement,
+// interpolated in CIELAB so the steps look even
+func (c RGB) GradientToComplement(n int) []RGB {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]RGB, n)
+	out[0] = c
+	if n == 1 {
+		return out
+	}
+	comp := c.Complement()
+	for i := 1; i < n-1; i++ {
+		out[i] = mixLab(c, comp, float64(i)/float64(n-1))
+	}
+	out[n-1] = comp
+	return out
+}
+
+// GradientVia returns n colors running from start through mid to end,
+// interpolated in CIELAB, with start to mid spanning the first half and mid
+// to end the second. When n is odd the center sample is exactly mid.
+func GradientVia(start, mid, end RGB, n int) []RGB {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]RGB, n)
+	if n == 1 {
+		out[0] = mid
+		return out
+	}
+	for i := range out {
+		switch t := 2 * float64(i) / float64(n-1); {
+		case i == 0:
+			out[i] = start
+		case i == n-1:
+			out[i] = end
+		case t == 1:
+			out[i] = mid
+		case t < 1:
+			out[i] = mixLab(start, mid, t)
+		default:
+			out[i] = mixLab(mid, end, t-1)
+		}
+	}
+	return out
+}
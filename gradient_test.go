@@ -0,0 +1,169 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestGradientAt(t *testing.T) {
+	red, green, blue := RGB{1, 0, 0}, RGB{0, 1, 0}, RGB{0, 0, 1}
+	stops := []RGB{red, green, blue}
+	for _, test := range []struct {
+		t    float64
+		want RGB
+	}{
+		{-1, red},
+		{0, red},
+		{0.25, RGB{0.5, 0.5, 0}},
+		{0.5, green},
+		{0.625, RGB{0, 0.75, 0.25}},
+		{1, blue},
+		{2, blue},
+	} {
+		if have := GradientAt(stops, test.t); !eqRGB(have, test.want) {
+			t.Errorf("%.3f: have %v, want %v", test.t, have, test.want)
+		}
+	}
+	if have := GradientAt([]RGB{green}, 0.3); have != green {
+		t.Errorf("single stop: have %v, want %v", have, green)
+	}
+}
+
+func TestCSSLinearGradient(t *testing.T) {
+	for _, test := range []struct {
+		stops []RGB
+		angle float64
+		want  string
+	}{
+		{[]RGB{{1, 0, 0}, {0, 0, 1}}, 90, "linear-gradient(90deg, #ff0000 0%, #0000ff 100%)"},
+		{[]RGB{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, 45.5, "linear-gradient(45.5deg, #ff0000 0%, #00ff00 50%, #0000ff 100%)"},
+		{[]RGB{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}, {1, 1, 1}}, 0, "linear-gradient(0deg, #000000 0%, #000000 33.3333%, #000000 66.6667%, #ffffff 100%)"},
+	} {
+		if have := CSSLinearGradient(test.stops, test.angle); have != test.want {
+			t.Errorf("have %s, want %s", have, test.want)
+		}
+	}
+}
+
+func TestGradientToComplement(t *testing.T) {
+	for _, c := range []RGB{{1, 0, 0}, {0.2, 0.6, 0.4}, {0.5, 0.5, 0.5}} {
+		for _, n := range []int{1, 2, 5, 10} {
+			ramp := c.GradientToComplement(n)
+			if len(ramp) != n {
+				t.Errorf("%s %2d: have %d colors", c.ToHTML(), n, len(ramp))
+				continue
+			}
+			if ramp[0] != c {
+				t.Errorf("%s %2d: starts at %v", c.ToHTML(), n, ramp[0])
+			}
+			if n > 1 && !eqRGB(ramp[n-1], c.Complement()) {
+				t.Errorf("%s %2d: ends at %v, want %v", c.ToHTML(), n, ramp[n-1], c.Complement())
+			}
+		}
+	}
+}
+
+func TestGradientVia(t *testing.T) {
+	start, mid, end := RGB{0, 0, 0.6}, RGB{1, 1, 1}, RGB{0.8, 0.1, 0}
+	for _, n := range []int{3, 5, 9, 21} {
+		ramp := GradientVia(start, mid, end, n)
+		if len(ramp) != n {
+			t.Fatalf("%2d: have %d colors", n, len(ramp))
+		}
+		if ramp[0] != start || ramp[n/2] != mid || ramp[n-1] != end {
+			t.Errorf("%2d: have %v, %v, %v at the ends and center", n, ramp[0], ramp[n/2], ramp[n-1])
+		}
+		// the first half only ever lightens toward white
+		for i := 1; i <= n/2; i++ {
+			if ramp[i].ToLab().L < ramp[i-1].ToLab().L {
+				t.Errorf("%2d: L* falls at %d", n, i)
+			}
+		}
+	}
+}
+
+func TestCyclicGradient(t *testing.T) {
+	red, green, blue := RGB{1, 0, 0}, RGB{0, 1, 0}, RGB{0, 0, 1}
+	have := CyclicGradient([]RGB{red, green, blue}, 6)
+	want := []RGB{red, {0.5, 0.5, 0}, green, {0, 0.5, 0.5}, blue, {0.5, 0, 0.5}}
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d", len(have), len(want))
+	}
+	for i := range want {
+		if !eqRGB(have[i], want[i]) {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+
+	// wrapping from the last frame to the first is a step like any other
+	loop := CyclicGradient([]RGB{red, blue}, 100)
+	step := real.Abs(loop[1].R - loop[0].R)
+	for i := range loop {
+		next := loop[(i+1)%len(loop)]
+		if d := real.Abs(next.R - loop[i].R); real.Diff(d, step) > epsilonF {
+			t.Errorf("%d to %d: red moves by %f, want %f", i, (i+1)%len(loop), d, step)
+		}
+	}
+}
+
+func TestGradient(t *testing.T) {
+	a, b := RGB{0.1, 0.2, 0.3}, RGB{0.7, 0.9, 0.35}
+	for _, n := range []int{2, 3, 7, 100} {
+		have := Gradient(a, b, n)
+		if len(have) != n {
+			t.Fatalf("%3d: have %d colors", n, len(have))
+		}
+		if have[0] != a || have[n-1] != b {
+			t.Errorf("%3d: runs from %v to %v, want exactly %v to %v", n, have[0], have[n-1], a, b)
+		}
+		for i, c := range have {
+			if want := Mix(a, b, float64(i)/float64(n-1)); !eqRGB(c, want) {
+				t.Errorf("%3d: step %d is %v, want %v", n, i, c, want)
+			}
+		}
+	}
+	for _, n := range []int{-1, 0, 1} {
+		if have := Gradient(a, b, n); len(have) != 0 {
+			t.Errorf("%d: have %v, want none", n, have)
+		}
+	}
+}
+
+func TestGradientStops(t *testing.T) {
+	stops := []RGB{{1, 0, 0}, {0.2, 0.4, 0.6}, {0, 0, 1}}
+	have := GradientStops(stops, 5)
+	want := []RGB{stops[0], Mix(stops[0], stops[1], 0.5), stops[1], Mix(stops[1], stops[2], 0.5), stops[2]}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+	if have := GradientStops(nil, 5); have != nil {
+		t.Errorf("no stops: have %v", have)
+	}
+}
+
+func TestGradientPerceptualLength(t *testing.T) {
+	red, yellow, blue := RGB{1, 0, 0}, RGB{1, 1, 0}, RGB{0, 0, 1}
+	stops := []RGB{red, yellow, blue}
+
+	have := GradientPerceptualLength(stops)
+	want := GradientPerceptualLength(stops[:2]) + GradientPerceptualLength(stops[1:])
+	if real.Diff(have, want) > epsilonF {
+		t.Errorf("have %v, want the sum of its legs %v", have, want)
+	}
+	if d := DistanceCIEDE2000(red, yellow) + DistanceCIEDE2000(yellow, blue); real.Diff(have, d) > epsilonF {
+		t.Errorf("have %v, want %v", have, d)
+	}
+	// a detour is never shorter than the direct route
+	if direct := GradientPerceptualLength([]RGB{red, blue}); have < direct {
+		t.Errorf("via yellow %v is shorter than direct %v", have, direct)
+	}
+
+	for _, stops := range [][]RGB{nil, {red}, {red, red}} {
+		if l := GradientPerceptualLength(stops); l != 0 {
+			t.Errorf("%v: have %v, want 0", stops, l)
+		}
+	}
+}
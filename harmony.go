@@ -0,0 +1,69 @@
+package color
+
+import "math"
+
+// Thresholds for the Clash heuristic, on the [0, 1] scales of HSL
+const (
+	clashHueSame   = 0.02     // hues closer than this read as the same hue
+	clashHueNear   = 1.0 / 12 // hues closer than this read as neighbors, one wheel step
+	clashSatSpread = 0.5      // a saturation gap wider than this muddies a shared hue
+	clashVivid     = 0.5      // saturation above which near hues start to vibrate
+	clashLightNear = 0.15     // lightness gap below which near hues vibrate
+)
+
+// Clash reports whether a and b are likely to clash, going by two rules of thumb:
+//   - colors of the same or neighboring hue but very different saturation,
+//     which makes the duller one look dirty next to the other
+//   - vivid colors of similar lightness whose hues are near but not equal,
+//     which seem to vibrate against each other rather than read as one hue
+//
+// Grays go with everything, so a pair including one never clashes.
+func Clash(a, b RGB) bool {
+	if _, ok := a.StableHue(); !ok {
+		return false
+	}
+	if _, ok := b.StableHue(); !ok {
+		return false
+	}
+	ha, hb := a.ToHSL(), b.ToHSL()
+	d := hueDistance(ha.H, hb.H)
+	if d > clashHueNear {
+		return false
+	}
+	if math.Abs(ha.S-hb.S) > clashSatSpread {
+		return true
+	}
+	return d > clashHueSame && min(ha.S, hb.S) > clashVivid && math.Abs(ha.L-hb.L) < clashLightNear
+}
+
+// rotate returns c with its hue turned by turns, wrapping around the wheel
+func (c HSL) rotate(turns float64) HSL {
+	c.H = wrapHue(c.H + turns)
+	return c
+}
+
+// Analogous returns n colors whose hues step by spread, in turns, centered on
+// c's hue, which is included when n is odd. Saturation and lightness are kept.
+func (c HSL) Analogous(n int, spread float64) []HSL {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]HSL, n)
+	for i := range out {
+		out[i] = c.rotate((float64(i) - float64(n-1)/2) * spread)
+	}
+	return out
+}
+
+// Triadic returns c and the two colors a third and two thirds of the way
+// around the wheel from it. Saturation and lightness are kept.
+func (c HSL) Triadic() [3]HSL {
+	return [3]HSL{c, c.rotate(1 / 3.0), c.rotate(2 / 3.0)}
+}
+
+// Tetradic returns c and the three colors a quarter, half, and three quarters
+// of the way around the wheel from it, a square on the wheel.
+// Saturation and lightness are kept.
+func (c HSL) Tetradic() [4]HSL {
+	return [4]HSL{c, c.rotate(0.25), c.rotate(0.5), c.rotate(0.75)}
+}
@@ -0,0 +1,65 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestClash(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a, b HSL
+		want bool
+	}{
+		{"muddy red", HSL{0, 1, 0.5}, HSL{0.02, 0.15, 0.5}, true},
+		{"vibrating reds", HSL{0, 1, 0.5}, HSL{0.05, 0.9, 0.5}, true},
+		{"complements", HSL{0.6, 0.7, 0.3}, HSL{0.08, 0.8, 0.6}, false},
+		{"tints", HSL{0.6, 0.7, 0.3}, HSL{0.6, 0.7, 0.8}, false},
+		{"near hues, far lightness", HSL{0, 1, 0.3}, HSL{0.05, 0.9, 0.7}, false},
+		{"gray", HSL{0, 0, 0.5}, HSL{0.3, 1, 0.5}, false},
+	} {
+		if have := Clash(test.a.ToRGB(), test.b.ToRGB()); have != test.want {
+			t.Errorf("%s: have %v, want %v", test.name, have, test.want)
+		}
+	}
+}
+
+func TestHarmonies(t *testing.T) {
+	for range nTrials {
+		c := Random[HSL]().(HSL)
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			tri := c.Triadic()
+			for i := range tri {
+				next := tri[(i+1)%len(tri)]
+				if d := wrapHue(next.H - tri[i].H); real.Diff(d, 1/3.0) > epsilonF {
+					t.Errorf("triadic %d: next hue is %f on, want 1/3", i, d)
+				}
+			}
+			tet := c.Tetradic()
+			for i := range tet {
+				next := tet[(i+1)%len(tet)]
+				if d := wrapHue(next.H - tet[i].H); real.Diff(d, 0.25) > epsilonF {
+					t.Errorf("tetradic %d: next hue is %f on, want 1/4", i, d)
+				}
+			}
+			for _, h := range append(tri[:], tet[:]...) {
+				if h.S != c.S || h.L != c.L || h.H < 0 || h.H >= 1 {
+					t.Errorf("have %v from %v", h, c)
+				}
+			}
+		})
+	}
+
+	base := HSL{0.98, 0.6, 0.5}
+	an := base.Analogous(5, 0.05)
+	want := []float64{0.88, 0.93, 0.98, 0.03, 0.08}
+	for i := range want {
+		if real.Diff(an[i].H, want[i]) > 1e-9 {
+			t.Errorf("analogous %d: have hue %f, want %f", i, an[i].H, want[i])
+		}
+	}
+	if have := base.Analogous(2, 0.1); real.Diff(have[0].H, 0.93) > 1e-9 || real.Diff(have[1].H, 0.03) > 1e-9 {
+		t.Errorf("analogous pair: have %v", have)
+	}
+}
@@ -0,0 +1,25 @@
+package color
+
+import "hash/fnv"
+
+// Bounds on the saturation and lightness of colors made by FromString,
+// which keep them pleasant and legible against both black and white
+const (
+	hashMinS, hashMaxS = 0.5, 0.75
+	hashMinL, hashMaxL = 0.45, 0.6
+)
+
+// FromString returns a stable color for s, for coloring avatars, tags, and the like.
+// The FNV-1a hash of s picks the hue, and a few more of its bits vary the
+// saturation and lightness within a narrow, pleasant band.
+func FromString(s string) RGB {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	sum := h.Sum64()
+
+	// the top 32 bits pick the hue, the next two bytes the saturation and lightness
+	hue := float64(sum>>32) / (1 << 32)
+	sat := hashMinS + (hashMaxS-hashMinS)*float64(sum>>24&0xff)/0xff
+	light := hashMinL + (hashMaxL-hashMinL)*float64(sum>>16&0xff)/0xff
+	return HSL{hue, sat, light}.ToRGB()
+}
@@ -0,0 +1,23 @@
+package color
+
+import "testing"
+
+func TestFromString(t *testing.T) {
+	labels := []string{"", "alice", "bob", "carol", "bug", "feature", "wontfix", "Alice"}
+	seen := map[RGB]string{}
+	for _, s := range labels {
+		c := FromString(s)
+		if again := FromString(s); again != c {
+			t.Errorf("%q: have %v then %v", s, c, again)
+		}
+		if other, ok := seen[c]; ok {
+			t.Errorf("%q and %q share %v", s, other, c)
+		}
+		seen[c] = s
+
+		hsl := c.ToHSL()
+		if hsl.S < hashMinS-epsilonF || hsl.S > hashMaxS+epsilonF || hsl.L < hashMinL-epsilonF || hsl.L > hashMaxL+epsilonF {
+			t.Errorf("%q: %v falls outside the pleasant band", s, hsl)
+		}
+	}
+}
@@ -0,0 +1,594 @@
+package color
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+const (
+	// The WCAG AA contrast ratio for normal sized text
+	minTextContrast = 4.5
+	// The share of a region that may fail the contrast check before text needs a shadow
+	shadowTolerance = 0.1
+)
+
+// at returns the pixel of img at (x, y) as an RGB
+func at(img image.Image, x, y int) RGB {
+	return rgbModel(img.At(x, y)).(RGB)
+}
+
+// NeedsTextShadow reports whether text drawn in fg over the given region of bg
+// needs a shadow to stay legible. That is the case when fg doesn't contrast
+// enough with the mean luminance of the region, or when more than a tenth of
+// the region's pixels fail the contrast check on their own.
+// The region is clipped to the bounds of bg.
+func NeedsTextShadow(fg RGB, bg image.Image, region image.Rectangle) bool {
+	region = region.Intersect(bg.Bounds())
+	if region.Empty() {
+		return false
+	}
+
+	lf := fg.Luminance()
+	var sum float64
+	var failing int
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			l := at(bg, x, y).Luminance()
+			sum += l
+			if contrast(lf, l) < minTextContrast {
+				failing++
+			}
+		}
+	}
+
+	n := float64(region.Dx() * region.Dy())
+	return contrast(lf, sum/n) < minTextContrast || float64(failing)/n > shadowTolerance
+}
+
+// The two tones of a transparency checkerboard
+var (
+	checkerLight = color.RGBA{0xcc, 0xcc, 0xcc, 0xff}
+	checkerDark  = color.RGBA{0x99, 0x99, 0x99, 0xff}
+)
+
+// TransparencyCheckerboard returns a w×h image of the light and dark gray
+// checker pattern used to preview transparency, with square cells of the given size.
+// The top left cell is light.
+func TransparencyCheckerboard(w, h, cell int) *image.RGBA {
+	cell = max(cell, 1)
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if (x/cell+y/cell)%2 == 0 {
+				img.SetRGBA(x, y, checkerLight)
+			} else {
+				img.SetRGBA(x, y, checkerDark)
+			}
+		}
+	}
+	return img
+}
+
+// lerp16 interpolates between a pair of 16 bit channel values
+func lerp16(a, b uint32, t float64) uint16 {
+	return uint16(float64(a)*(1-t) + float64(b)*t + 0.5)
+}
+
+// OverlayColor composites a solid layer of c over img at the given opacity.
+// Opacity is clamped into [0, 1]; 0 reproduces img and 1 gives a solid image of c.
+func OverlayColor(img image.Image, c RGB, opacity float64) *image.RGBA {
+	opacity = clamp01(opacity)
+	cr, cg, cb, ca := c.RGBA()
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			out.Set(x, y, color.RGBA64{
+				lerp16(r, cr, opacity),
+				lerp16(g, cg, opacity),
+				lerp16(b, cb, opacity),
+				lerp16(a, ca, opacity),
+			})
+		}
+	}
+	return out
+}
+
+// The largest number of samples per axis that whole-image statistics read
+const maxSamples = 256
+
+// sampleSteps returns the strides along each axis that keep a walk over bounds
+// within maxSamples per axis
+func sampleSteps(bounds image.Rectangle) (dx, dy int) {
+	dx = max(1, (bounds.Dx()+maxSamples-1)/maxSamples)
+	dy = max(1, (bounds.Dy()+maxSamples-1)/maxSamples)
+	return
+}
+
+// AverageColor returns the mean color of img.
+// Channels are averaged in linear light so that, for instance, red and blue
+// average to a bright purple rather than the muddy one naive averaging gives.
+// Large images are sampled on an evenly spaced grid for speed.
+func AverageColor(img image.Image) RGB {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return RGB{}
+	}
+
+	dx, dy := sampleSteps(bounds)
+	var r, g, b, n float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += dy {
+		for x := bounds.Min.X; x < bounds.Max.X; x += dx {
+			c := at(img, x, y)
+			r += Linearize(c.R)
+			g += Linearize(c.G)
+			b += Linearize(c.B)
+			n++
+		}
+	}
+	return RGB{Delinearize(r / n), Delinearize(g / n), Delinearize(b / n)}
+}
+
+// ColorEntropy measures the color diversity of img as the Shannon entropy, in bits,
+// of a histogram that splits each channel into the given number of bins.
+// A solid image scores 0 and uniform noise approaches log2(bins³).
+func ColorEntropy(img image.Image, bins int) float64 {
+	bins = max(bins, 1)
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+
+	bin := func(v float64) int {
+		return min(int(v*float64(bins)), bins-1)
+	}
+	hist := make([]int, bins*bins*bins)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := at(img, x, y)
+			hist[(bin(c.R)*bins+bin(c.G))*bins+bin(c.B)]++
+		}
+	}
+
+	n := float64(bounds.Dx() * bounds.Dy())
+	var entropy float64
+	for _, count := range hist {
+		if count > 0 {
+			p := float64(count) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// MeanDeltaE returns the CIEDE2000 difference between a and b averaged over
+// every pair of corresponding pixels. The images must be the same size,
+// though their bounds needn't share an origin.
+func MeanDeltaE(a, b image.Image) (float64, error) {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() {
+		return 0, fmt.Errorf("image sizes differ: %v and %v", ab.Size(), bb.Size())
+	}
+	if ab.Empty() {
+		return 0, nil
+	}
+
+	var sum float64
+	for y := range ab.Dy() {
+		for x := range ab.Dx() {
+			sum += DistanceCIEDE2000(at(a, ab.Min.X+x, ab.Min.Y+y), at(b, bb.Min.X+x, bb.Min.Y+y))
+		}
+	}
+	return sum / float64(ab.Dx()*ab.Dy()), nil
+}
+
+// nrgbAt returns the pixel of img at (x, y) as an RGB that isn't premultiplied by alpha, along with its alpha
+func nrgbAt(img image.Image, x, y int) (RGB, uint16) {
+	n := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+	return RGB{float64(n.R) / 0xffff, float64(n.G) / 0xffff, float64(n.B) / 0xffff}, n.A
+}
+
+// mapRGB returns a copy of img with f applied to every pixel, leaving alpha untouched
+func mapRGB(img image.Image, f func(RGB) RGB) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, a := nrgbAt(img, x, y)
+			r, g, b, _ := f(c).RGBA()
+			out.Set(x, y, color.NRGBA64{uint16(r), uint16(g), uint16(b), a})
+		}
+	}
+	return out
+}
+
+// MapHSL returns a copy of img with f applied to the HSL form of every pixel.
+// Alpha is carried over untouched.
+func MapHSL(img image.Image, f func(HSL) HSL) *image.RGBA {
+	return mapRGB(img, func(c RGB) RGB {
+		return f(c.ToHSL()).ToRGB()
+	})
+}
+
+// HueRotateImage returns a copy of img with the hue of every pixel rotated by the given angle
+func HueRotateImage(img image.Image, degrees float64) *image.RGBA {
+	turn := degrees / 360
+	return MapHSL(img, func(c HSL) HSL {
+		c.H = wrapHue(c.H + turn)
+		return c
+	})
+}
+
+// The number of levels EqualizeLuminance sorts lightness into
+const equalizeLevels = 256
+
+// EqualizeLuminance spreads the lightness of img over the full range by histogram
+// equalization of CIELAB L*. The a* and b* channels are left alone so hues don't shift.
+func EqualizeLuminance(img image.Image) *image.RGBA {
+	level := func(c RGB) int {
+		return min(max(int(c.ToLab().L/100*equalizeLevels), 0), equalizeLevels-1)
+	}
+
+	var cdf [equalizeLevels]int
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, _ := nrgbAt(img, x, y)
+			cdf[level(c)]++
+		}
+	}
+	for i := 1; i < equalizeLevels; i++ {
+		cdf[i] += cdf[i-1]
+	}
+
+	total := bounds.Dx() * bounds.Dy()
+	var lowest int
+	for _, n := range cdf {
+		if n > 0 {
+			lowest = n
+			break
+		}
+	}
+	if total == lowest {
+		// a single level has nothing to spread
+		return mapRGB(img, func(c RGB) RGB { return c })
+	}
+
+	return mapRGB(img, func(c RGB) RGB {
+		lab := c.ToLab()
+		lab.L = 100 * float64(cdf[level(c)]-lowest) / float64(total-lowest)
+		return lab.fitGamut()
+	})
+}
+
+// The most rounds of refinement DominantPair makes
+const dominantRounds = 16
+
+// DominantPair splits the colors of img into two clusters by 2-means in CIELAB
+// and returns their centroids, the one covering more of the image first.
+// Large images are sampled on a grid as in AverageColor.
+func DominantPair(img image.Image) (RGB, RGB) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return RGB{}, RGB{}
+	}
+
+	dx, dy := sampleSteps(bounds)
+	var samples []Lab
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += dy {
+		for x := bounds.Min.X; x < bounds.Max.X; x += dx {
+			samples = append(samples, at(img, x, y).ToLab())
+		}
+	}
+
+	// seed with the first sample and whichever sample lies farthest from it
+	dist := func(a, b Lab) float64 {
+		return (a.L-b.L)*(a.L-b.L) + (a.A-b.A)*(a.A-b.A) + (a.B-b.B)*(a.B-b.B)
+	}
+	centroids := [2]Lab{samples[0], samples[0]}
+	for _, s := range samples {
+		if dist(s, centroids[0]) > dist(centroids[1], centroids[0]) {
+			centroids[1] = s
+		}
+	}
+
+	var counts [2]int
+	for range dominantRounds {
+		var sums [2]Lab
+		counts = [2]int{}
+		for _, s := range samples {
+			i := 0
+			if dist(s, centroids[1]) < dist(s, centroids[0]) {
+				i = 1
+			}
+			sums[i].L += s.L
+			sums[i].A += s.A
+			sums[i].B += s.B
+			counts[i]++
+		}
+
+		moved := false
+		for i, sum := range sums {
+			if counts[i] == 0 {
+				continue
+			}
+			n := float64(counts[i])
+			next := Lab{sum.L / n, sum.A / n, sum.B / n}
+			moved = moved || next != centroids[i]
+			centroids[i] = next
+		}
+		if !moved {
+			break
+		}
+	}
+
+	a, b := centroids[0].ToRGB().Clamp(), centroids[1].ToRGB().Clamp()
+	if counts[1] > counts[0] {
+		a, b = b, a
+	}
+	return a, b
+}
+
+// UniqueColors returns every distinct color in img at 8 bit precision,
+// in the order they are first met scanning row by row. Alpha is ignored, so a
+// color seen at several opacities counts once.
+func UniqueColors(img image.Image) []RGB {
+	bounds := img.Bounds()
+	seen := map[uint32]struct{}{}
+	var out []RGB
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			key := uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, RGB{float64(c.R) / 0xff, float64(c.G) / 0xff, float64(c.B) / 0xff})
+		}
+	}
+	return out
+}
+
+// PaletteStrip renders colors side by side as cellWidth×height blocks, for previews
+func PaletteStrip(colors []RGB, cellWidth, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, len(colors)*cellWidth, height))
+	for i, c := range colors {
+		cell := image.Rect(i*cellWidth, 0, (i+1)*cellWidth, height)
+		draw.Draw(img, cell, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// ColorCast estimates the tint of img under the gray world assumption, that a
+// typical scene averages out to gray. It returns the HSL of the image's average
+// color, whose hue is that of the cast, and the cast's strength as the CIELAB
+// chroma of that average. Neutral images have a strength near 0 and a
+// meaningless hue; a strength above 5 or so is a visible cast.
+func ColorCast(img image.Image) (HSL, float64) {
+	avg := AverageColor(img)
+	lab := avg.ToLab()
+	return avg.ToHSL(), math.Hypot(lab.A, lab.B)
+}
+
+// WBMethod selects how AutoWhiteBalance estimates the illuminant
+type WBMethod int
+
+const (
+	// GrayWorld assumes the scene averages out to gray
+	GrayWorld WBMethod = iota
+	// WhitePatch assumes the brightest pixel is white
+	WhitePatch
+)
+
+// AutoWhiteBalance removes a color cast from img by scaling each channel in
+// linear light. GrayWorld scales the channels so the image's average becomes
+// neutral at the same overall level, and WhitePatch so its brightest pixel,
+// by luminance, becomes white. Channels pushed past full are clipped.
+// Unknown methods return an unchanged copy.
+func AutoWhiteBalance(img image.Image, method WBMethod) *image.RGBA {
+	gain := [3]float64{1, 1, 1}
+	switch method {
+	case GrayWorld:
+		avg := AverageColor(img)
+		lin := [3]float64{Linearize(avg.R), Linearize(avg.G), Linearize(avg.B)}
+		gray := (lin[0] + lin[1] + lin[2]) / 3
+		for i, v := range lin {
+			if v > 0 {
+				gain[i] = gray / v
+			}
+		}
+	case WhitePatch:
+		bounds := img.Bounds()
+		var brightest RGB
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if c := at(img, x, y); c.Luminance() > brightest.Luminance() {
+					brightest = c
+				}
+			}
+		}
+		for i, v := range []float64{brightest.R, brightest.G, brightest.B} {
+			if v > 0 {
+				gain[i] = 1 / Linearize(v)
+			}
+		}
+	}
+
+	return mapRGB(img, func(c RGB) RGB {
+		return RGB{
+			Delinearize(min(Linearize(c.R)*gain[0], 1)),
+			Delinearize(min(Linearize(c.G)*gain[1], 1)),
+			Delinearize(min(Linearize(c.B)*gain[2], 1)),
+		}
+	})
+}
+
+// ReplaceColor returns a copy of img with every pixel within tolerance of from,
+// by CIEDE2000, replaced by to. Other pixels, and the alpha of all of them,
+// are left as they were.
+func ReplaceColor(img image.Image, from, to RGB, tolerance float64) *image.RGBA {
+	lab := from.ToLab()
+	return mapRGB(img, func(c RGB) RGB {
+		if ciede2000(lab, c.ToLab()) <= tolerance {
+			return to
+		}
+		return c
+	})
+}
+
+// SampleFlatColors returns the distinct colors, at 8 bit precision, of the
+// pixels of img lying in flat regions, those whose every horizontal and
+// vertical neighbor is within threshold of them by CIEDE2000. Anti-aliased
+// edges and other blends between regions are skipped, leaving the colors a
+// flat design was drawn with. Colors come in the order they are first met
+// scanning row by row.
+func SampleFlatColors(img image.Image, threshold float64) []RGB {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	labs := make([]Lab, w*h)
+	for y := range h {
+		for x := range w {
+			labs[y*w+x] = at(img, bounds.Min.X+x, bounds.Min.Y+y).ToLab()
+		}
+	}
+
+	seen := map[uint32]struct{}{}
+	var out []RGB
+	for y := range h {
+		for x := range w {
+			lab := labs[y*w+x]
+			flat := true
+			for _, d := range []image.Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				nx, ny := x+d.X, y+d.Y
+				if nx < 0 || nx >= w || ny < 0 || ny >= h {
+					continue
+				}
+				if ciede2000(lab, labs[ny*w+nx]) > threshold {
+					flat = false
+					break
+				}
+			}
+			if !flat {
+				continue
+			}
+			c := at(img, bounds.Min.X+x, bounds.Min.Y+y)
+			key := c.ToHexInt()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, FromHexInt(key))
+		}
+	}
+	return out
+}
+
+// RegionHSL returns the representative HSL of the part of img within r: the
+// mean of its hues weighted by saturation, taken around the wheel so reds
+// either side of 0 average to red, and the plain means of saturation and
+// lightness. r is clamped to the image bounds, and an empty region gives the
+// zero HSL, as does the hue of an all gray one.
+func RegionHSL(img image.Image, r image.Rectangle) HSL {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return HSL{}
+	}
+	var x, y, s, l float64
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			c := at(img, px, py).ToHSL()
+			hx, hy := hueComponents(c.H)
+			x += c.S * hx
+			y += c.S * hy
+			s += c.S
+			l += c.L
+		}
+	}
+	n := float64(r.Dx() * r.Dy())
+	var h float64
+	if x != 0 || y != 0 {
+		h = hueFromComponents(x, y)
+	}
+	return HSL{h, s / n, l / n}
+}
+
+// Quantize picks up to n colors representing img by median cut: starting from
+// a box around all of its colors, the box spanning the widest range along any
+// channel is repeatedly split at its median along that channel, until there
+// are n boxes or none can be split. Each box contributes its mean color.
+// Large images are sampled as in AverageColor.
+func Quantize(img image.Image, n int) Palette {
+	bounds := img.Bounds()
+	if n <= 0 || bounds.Empty() {
+		return nil
+	}
+	dx, dy := sampleSteps(bounds)
+	var pixels []RGB
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += dy {
+		for x := bounds.Min.X; x < bounds.Max.X; x += dx {
+			pixels = append(pixels, at(img, x, y))
+		}
+	}
+
+	channel := func(c RGB, i int) float64 {
+		return [3]float64{c.R, c.G, c.B}[i]
+	}
+	// widest returns the channel the colors in box span most widely, and that span
+	widest := func(box []RGB) (channel int, span float64) {
+		lo, hi := [3]float64{1, 1, 1}, [3]float64{}
+		for _, c := range box {
+			for i, v := range [3]float64{c.R, c.G, c.B} {
+				lo[i], hi[i] = min(lo[i], v), max(hi[i], v)
+			}
+		}
+		for i := range lo {
+			if hi[i]-lo[i] > span {
+				channel, span = i, hi[i]-lo[i]
+			}
+		}
+		return
+	}
+
+	boxes := [][]RGB{pixels}
+	for len(boxes) < n {
+		best, ch, span := -1, 0, 0.0
+		for i, box := range boxes {
+			if c, s := widest(box); s > span {
+				best, ch, span = i, c, s
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best]
+		slices.SortFunc(box, func(a, b RGB) int {
+			return cmp.Compare(channel(a, ch), channel(b, ch))
+		})
+		// split between differing values, so neither half is empty
+		mid := len(box) / 2
+		for mid > 0 && channel(box[mid-1], ch) == channel(box[mid], ch) {
+			mid--
+		}
+		if mid == 0 {
+			mid = len(box) / 2
+			for mid < len(box) && channel(box[mid-1], ch) == channel(box[mid], ch) {
+				mid++
+			}
+		}
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	out := make(Palette, len(boxes))
+	for i, box := range boxes {
+		out[i] = ChannelMean(box)
+	}
+	return out
+}
@@ -0,0 +1,417 @@
+package color
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var (
+	black = RGB{0, 0, 0}
+	white = RGB{1, 1, 1}
+)
+
+// solid returns a w×h image filled with c
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+// halves returns a w×h image whose left half is l and whose right half is r
+func halves(w, h int, l, r color.Color) *image.RGBA {
+	img := solid(w, h, l)
+	draw.Draw(img, image.Rect(w/2, 0, w, h), image.NewUniform(r), image.Point{}, draw.Src)
+	return img
+}
+
+func TestNeedsTextShadow(t *testing.T) {
+	img := halves(20, 10, black, white)
+	for _, test := range []struct {
+		name   string
+		region image.Rectangle
+		want   bool
+	}{
+		{"dark", image.Rect(0, 0, 10, 10), false},
+		{"light", image.Rect(10, 0, 20, 10), true},
+		{"mixed", image.Rect(5, 0, 15, 10), true},
+		{"mostly dark", image.Rect(0, 0, 11, 10), false},
+	} {
+		if have := NeedsTextShadow(white, img, test.region); have != test.want {
+			t.Errorf("%s: have %v, want %v", test.name, have, test.want)
+		}
+	}
+}
+
+func TestTransparencyCheckerboard(t *testing.T) {
+	const cell = 4
+	img := TransparencyCheckerboard(16, 12, cell)
+	if have, want := img.Bounds(), image.Rect(0, 0, 16, 12); have != want {
+		t.Fatalf("bounds: have %v, want %v", have, want)
+	}
+	for y := 0; y < 12; y += cell {
+		for x := 0; x < 16; x += cell {
+			want := checkerLight
+			if (x/cell+y/cell)%2 == 1 {
+				want = checkerDark
+			}
+			if have := img.RGBAAt(x, y); have != want {
+				t.Errorf("(%2d, %2d): have %v, want %v", x, y, have, want)
+			}
+			if have := img.RGBAAt(x+cell-1, y+cell-1); have != want {
+				t.Errorf("(%2d, %2d): have %v, want %v", x+cell-1, y+cell-1, have, want)
+			}
+		}
+	}
+	if img.RGBAAt(0, 0) == img.RGBAAt(cell, 0) {
+		t.Errorf("neighboring cells share a color")
+	}
+}
+
+func TestOverlayColor(t *testing.T) {
+	img := halves(4, 2, black, white)
+	red := RGB{1, 0, 0}
+	for _, test := range []struct {
+		opacity     float64
+		left, right color.RGBA
+	}{
+		{0, color.RGBA{0, 0, 0, 0xff}, color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{0.5, color.RGBA{0x80, 0, 0, 0xff}, color.RGBA{0xff, 0x80, 0x80, 0xff}},
+		{1, color.RGBA{0xff, 0, 0, 0xff}, color.RGBA{0xff, 0, 0, 0xff}},
+	} {
+		out := OverlayColor(img, red, test.opacity)
+		if have := out.RGBAAt(0, 0); have != test.left {
+			t.Errorf("%.1f left: have %v, want %v", test.opacity, have, test.left)
+		}
+		if have := out.RGBAAt(3, 1); have != test.right {
+			t.Errorf("%.1f right: have %v, want %v", test.opacity, have, test.right)
+		}
+	}
+}
+
+func TestAverageColor(t *testing.T) {
+	for _, size := range []int{2, 10, 1000} {
+		have := AverageColor(halves(size, size, RGB{1, 0, 0}, RGB{0, 0, 1}))
+		want := RGB{Delinearize(0.5), 0, Delinearize(0.5)}
+		if real.Diff(have.R, want.R) > 0.01 || have.G != 0 || real.Diff(have.B, want.B) > 0.01 {
+			t.Errorf("%4d: have %v, want %v", size, have, want)
+		}
+	}
+	if have := AverageColor(solid(3, 3, white)); !eqRGB(have, white) {
+		t.Errorf("white: have %v, want %v", have, white)
+	}
+}
+
+func TestColorEntropy(t *testing.T) {
+	const bins = 4
+	if have := ColorEntropy(solid(16, 16, RGB{0.2, 0.4, 0.6}), bins); have != 0 {
+		t.Errorf("solid: have %f, want 0", have)
+	}
+
+	// seeded, so the noise and its entropy are the same every run
+	r := rand.New(rand.NewSource(212))
+	noise := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			noise.Set(x, y, RGB{r.Float64(), r.Float64(), r.Float64()})
+		}
+	}
+	// uniform noise over 64 buckets tends to 6 bits
+	if have := ColorEntropy(noise, bins); have < 5.5 || have > 6 {
+		t.Errorf("noise: have %f, want about 6", have)
+	}
+}
+
+func TestMeanDeltaE(t *testing.T) {
+	a := halves(8, 8, RGB{1, 0, 0}, RGB{0, 0.5, 1})
+	if have, err := MeanDeltaE(a, a); err != nil || have != 0 {
+		t.Errorf("identical: have %f %v, want 0", have, err)
+	}
+
+	b := halves(8, 8, RGB{1, 0, 0}, RGB{0, 0.5, 1})
+	b.Set(0, 0, RGB{0.98, 0, 0})
+	if have, err := MeanDeltaE(a, b); err != nil || have <= 0 || have > 0.1 {
+		t.Errorf("one pixel off: have %f %v, want small but positive", have, err)
+	}
+
+	if _, err := MeanDeltaE(a, solid(8, 4, black)); err == nil {
+		t.Errorf("size mismatch: have no error")
+	}
+}
+
+func TestHueRotateImage(t *testing.T) {
+	img := halves(4, 4, RGB{1, 0, 0}, RGB{0.5, 0.5, 0.5})
+	for _, test := range []struct {
+		degrees float64
+		want    color.RGBA
+	}{
+		{0, color.RGBA{0xff, 0, 0, 0xff}},
+		{120, color.RGBA{0, 0xff, 0, 0xff}},
+		{240, color.RGBA{0, 0, 0xff, 0xff}},
+		{-120, color.RGBA{0, 0, 0xff, 0xff}},
+		{480, color.RGBA{0, 0xff, 0, 0xff}},
+	} {
+		out := HueRotateImage(img, test.degrees)
+		if have := out.RGBAAt(0, 0); have != test.want {
+			t.Errorf("%4.0f°: have %v, want %v", test.degrees, have, test.want)
+		}
+		if have, want := out.RGBAAt(3, 3), img.RGBAAt(3, 3); have != want {
+			t.Errorf("%4.0f° gray: have %v, want %v", test.degrees, have, want)
+		}
+	}
+}
+
+func TestEqualizeLuminance(t *testing.T) {
+	// a dim, low contrast ramp of grays and muted blues
+	img := image.NewRGBA(image.Rect(0, 0, 16, 2))
+	for x := range 16 {
+		v := 0.4 + 0.2*float64(x)/15
+		img.Set(x, 0, RGB{v, v, v})
+		img.Set(x, 1, RGB{v - 0.1, v - 0.1, v})
+	}
+	lightness := func(img image.Image) (lo, hi float64) {
+		lo, hi = 100, 0
+		for y := range 2 {
+			for x := range 16 {
+				l := at(img, x, y).ToLab().L
+				lo, hi = min(lo, l), max(hi, l)
+			}
+		}
+		return
+	}
+
+	out := EqualizeLuminance(img)
+	inLo, inHi := lightness(img)
+	outLo, outHi := lightness(out)
+	if outHi-outLo < 2*(inHi-inLo) {
+		t.Errorf("L* range grew from [%.1f, %.1f] to only [%.1f, %.1f]", inLo, inHi, outLo, outHi)
+	}
+	for x := range 16 {
+		if c := at(out, x, 0); real.Diff(c.R, c.G) > 0.01 || real.Diff(c.G, c.B) > 0.01 {
+			t.Errorf("gray %d picked up a tint: %v", x, c)
+		}
+		if h, ok := at(out, x, 1).StableHue(); ok && hueDistance(h, at(img, x, 1).ToHSL().H) > 0.05 {
+			t.Errorf("blue %d drifted to hue %.3f", x, h)
+		}
+	}
+}
+
+func TestDominantPair(t *testing.T) {
+	red, blue := RGB{0.9, 0.1, 0.1}, RGB{0.1, 0.2, 0.8}
+	img := solid(10, 10, blue)
+	draw.Draw(img, image.Rect(0, 0, 7, 10), image.NewUniform(red), image.Point{}, draw.Src)
+
+	first, second := DominantPair(img)
+	if d := DistanceCIEDE2000(first, red); d > 1 {
+		t.Errorf("first: have %s, want %s", first.ToHTML(), red.ToHTML())
+	}
+	if d := DistanceCIEDE2000(second, blue); d > 1 {
+		t.Errorf("second: have %s, want %s", second.ToHTML(), blue.ToHTML())
+	}
+}
+
+func TestUniqueColors(t *testing.T) {
+	img := halves(6, 4, RGB{1, 0, 0}, RGB{0, 0, 1})
+	img.Set(0, 3, color.RGBA{0x12, 0x34, 0x56, 0xff})
+	img.Set(5, 0, color.RGBA{0x12, 0x34, 0x56, 0xff})
+	img.Set(2, 2, color.RGBA64{0xff00, 0, 0, 0xffff}) // red at 8 bits
+
+	want := []RGB{{1, 0, 0}, {0, 0, 1}, {0x12 / 255.0, 0x34 / 255.0, 0x56 / 255.0}}
+	have := UniqueColors(img)
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d: %v", len(have), len(want), have)
+	}
+	for i := range want {
+		if !eqRGB(have[i], want[i]) {
+			t.Errorf("%d: have %v, want %v", i, have[i], want[i])
+		}
+	}
+
+	// the same color at two opacities is one color
+	translucent := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	translucent.Set(0, 0, color.NRGBA{0x12, 0x34, 0x56, 0xff})
+	translucent.Set(1, 0, color.NRGBA{0x12, 0x34, 0x56, 0x80})
+	if have := UniqueColors(translucent); len(have) != 1 || !eqRGB(have[0], want[2]) {
+		t.Errorf("alpha: have %v, want [%v]", have, want[2])
+	}
+}
+
+func TestPaletteStrip(t *testing.T) {
+	colors := []RGB{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {0.2, 0.4, 0.6}}
+	const cell, height = 5, 3
+	img := PaletteStrip(colors, cell, height)
+	if have, want := img.Bounds().Size(), image.Pt(len(colors)*cell, height); have != want {
+		t.Fatalf("size: have %v, want %v", have, want)
+	}
+	for i, c := range colors {
+		for _, p := range []image.Point{{i * cell, 0}, {(i+1)*cell - 1, height - 1}} {
+			if have := at(img, p.X, p.Y); have.ToHTML() != c.ToHTML() {
+				t.Errorf("%v: have %s, want %s", p, have.ToHTML(), c.ToHTML())
+			}
+		}
+	}
+}
+
+// scene returns a small image of assorted colors that averages out near gray
+func scene() *image.RGBA {
+	colors := []RGB{
+		{0.8, 0.2, 0.2}, {0.2, 0.8, 0.2}, {0.2, 0.2, 0.8},
+		{0.2, 0.8, 0.8}, {0.8, 0.2, 0.8}, {0.8, 0.8, 0.2},
+		{0.1, 0.1, 0.1}, {0.5, 0.5, 0.5}, {0.9, 0.9, 0.9},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 9, 9))
+	for y := range 9 {
+		for x := range 9 {
+			img.Set(x, y, colors[(x+y)%len(colors)])
+		}
+	}
+	return img
+}
+
+// tint scales the channels of every pixel of img by those of c
+func tint(img image.Image, c RGB) *image.RGBA {
+	return mapRGB(img, func(p RGB) RGB {
+		return RGB{p.R * c.R, p.G * c.G, p.B * c.B}
+	})
+}
+
+func TestColorCast(t *testing.T) {
+	if _, strength := ColorCast(scene()); strength > 2 {
+		t.Errorf("neutral: have strength %.2f, want near 0", strength)
+	}
+
+	cast, strength := ColorCast(tint(scene(), RGB{0.7, 1, 0.7}))
+	if strength < 10 {
+		t.Errorf("green cast: have strength %.2f, want a clear cast", strength)
+	}
+	if green := 1 / 3.0; hueDistance(cast.H, green) > 0.05 {
+		t.Errorf("green cast: have hue %.3f, want near %.3f", cast.H, green)
+	}
+}
+
+func TestAutoWhiteBalance(t *testing.T) {
+	cast := tint(scene(), RGB{0.7, 1, 0.7})
+	_, before := ColorCast(cast)
+	_, after := ColorCast(AutoWhiteBalance(cast, GrayWorld))
+	if after > before/4 {
+		t.Errorf("gray world: cast went from %.2f to only %.2f", before, after)
+	}
+
+	img := halves(4, 2, RGB{0.9, 1, 0.9}, RGB{0.3, 0.4, 0.3})
+	if have := at(AutoWhiteBalance(img, WhitePatch), 0, 0); !eqRGB(have, white) {
+		t.Errorf("white patch: have %v, want %v", have, white)
+	}
+}
+
+func TestReplaceColor(t *testing.T) {
+	red, green, blue := RGB{0.9, 0.1, 0.1}, RGB{0.1, 0.7, 0.2}, RGB{0, 0, 1}
+	img := halves(8, 4, red, green)
+	img.Set(1, 1, RGB{0.88, 0.12, 0.1}) // a shade off
+
+	out := ReplaceColor(img, red, blue, 5)
+	for y := range 4 {
+		for x := range 8 {
+			want := at(img, x, y)
+			if x < 4 {
+				want = blue
+			}
+			if have := at(out, x, y); have.ToHTML() != want.ToHTML() {
+				t.Errorf("(%d, %d): have %s, want %s", x, y, have.ToHTML(), want.ToHTML())
+			}
+		}
+	}
+
+	if have := at(ReplaceColor(img, red, blue, 0), 1, 1); have.ToHTML() == blue.ToHTML() {
+		t.Errorf("zero tolerance replaced a near match")
+	}
+}
+
+func TestSampleFlatColors(t *testing.T) {
+	red, blue := FromHexInt(0xcc1a1a), FromHexInt(0x1a33cc)
+	img := halves(12, 6, red, blue)
+	// a two pixel anti-aliased boundary between the regions
+	for y := range 6 {
+		img.Set(5, y, Mix(red, blue, 1/3.0))
+		img.Set(6, y, Mix(red, blue, 2/3.0))
+	}
+
+	have := SampleFlatColors(img, 2)
+	want := []RGB{red, blue}
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d: %v", len(have), len(want), have)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d: have %s, want %s", i, have[i].ToHTML(), want[i].ToHTML())
+		}
+	}
+
+	if have := UniqueColors(img); len(have) != 4 {
+		t.Errorf("all colors: have %d, want the 4 including the blends", len(have))
+	}
+}
+
+func TestRegionHSL(t *testing.T) {
+	// reds either side of the seam on the left, grays on the right
+	a, b := HSL{0.95, 1, 0.5}, HSL{0.05, 0.5, 0.3}
+	img := halves(8, 4, a.ToRGB(), RGB{0.5, 0.5, 0.5})
+	for y := range 4 {
+		img.Set(0, y, b.ToRGB())
+		img.Set(1, y, b.ToRGB())
+	}
+
+	// two columns each of a and b, weighted 2:1 toward a
+	have := RegionHSL(img, image.Rect(0, 0, 4, 4))
+	wantH := hueFromComponents(math.Cos(2*math.Pi*0.95)+0.5*math.Cos(2*math.Pi*0.05), math.Sin(2*math.Pi*0.95)+0.5*math.Sin(2*math.Pi*0.05))
+	if hueDistance(have.H, wantH) > 0.01 || real.Diff(have.S, 0.75) > 0.01 || real.Diff(have.L, 0.4) > 0.01 {
+		t.Errorf("left: have %v, want {%.3f 0.75 0.4}", have, wantH)
+	}
+	if hueDistance(have.H, 0) > 0.05 {
+		t.Errorf("left: hue %f isn't red", have.H)
+	}
+
+	// a region running off the image is clamped, here to the gray half
+	if have := RegionHSL(img, image.Rect(4, -10, 100, 100)); have.S != 0 || real.Diff(have.L, 0.5) > 0.01 {
+		t.Errorf("gray: have %v", have)
+	}
+	if have := RegionHSL(img, image.Rect(20, 20, 30, 30)); have != (HSL{}) {
+		t.Errorf("outside: have %v", have)
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	red, blue := FromHexInt(0xcc1a1a), FromHexInt(0x1a33cc)
+	p := Quantize(halves(8, 4, red, blue), 2)
+	if len(p) != 2 || !(eqRGB(p[0], red) && eqRGB(p[1], blue) || eqRGB(p[0], blue) && eqRGB(p[1], red)) {
+		t.Errorf("have %v, want %v and %v", p, red, blue)
+	}
+	if p := Quantize(halves(8, 4, red, blue), 8); len(p) != 2 {
+		t.Errorf("8 of 2 colors: have %v", p)
+	}
+	if p := Quantize(halves(8, 4, red, blue), 1); len(p) != 1 || !eqRGB(p[0], ChannelMean([]RGB{red, blue})) {
+		t.Errorf("1 color: have %v, want the mean", p)
+	}
+
+	// a ramp splits into equal bands
+	ramp := image.NewRGBA(image.Rect(0, 0, 64, 1))
+	for x := range 64 {
+		ramp.Set(x, 0, FromHexInt(uint32(x*4)<<16|0x8080))
+	}
+	p = Quantize(ramp, 4)
+	if len(p) != 4 {
+		t.Fatalf("ramp: have %d colors, want 4", len(p))
+	}
+	slices.SortFunc(p, func(a, b RGB) int { return cmp.Compare(a.R, b.R) })
+	for i, c := range p {
+		if want := (float64(i*16) + 7.5) * 4 / 255; real.Diff(c.R, want) > epsilonF {
+			t.Errorf("ramp band %d: have red %v, want %v", i, c.R, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package color
+
+// Interpolatable is a color whose components can be blended linearly, for
+// writing interpolation once across color spaces. FromComponents builds a
+// color of the same type from a slice like the one Components returns and
+// ignores its receiver, so it can be called on the zero value.
+type Interpolatable[T any] interface {
+	Components() []float64
+	FromComponents([]float64) T
+}
+
+// hued is implemented by the Interpolatable types with a hue among their
+// components, giving its index so LerpComponents can treat it as an angle
+type hued interface {
+	hueComponent() int
+}
+
+// LerpComponents interpolates from a to b by blending their components.
+// A hue, as in HSL and Oklch, is taken along the shorter arc of the wheel as
+// LerpHue does, so complementary hues meet at a hue a quarter turn from each
+// rather than at gray. t is clamped into [0, 1].
+func LerpComponents[T Interpolatable[T]](a, b T, t float64) T {
+	t = clamp01(t)
+	hue := -1
+	if h, ok := any(a).(hued); ok {
+		hue = h.hueComponent()
+	}
+	ca, cb := a.Components(), b.Components()
+	out := make([]float64, len(ca))
+	for i := range out {
+		if i == hue {
+			out[i] = LerpHue(ca[i], cb[i], t)
+		} else {
+			out[i] = lerp(ca[i], cb[i], t)
+		}
+	}
+	return a.FromComponents(out)
+}
+
+func (c RGB) Components() []float64 { return []float64{c.R, c.G, c.B} }
+
+func (RGB) FromComponents(v []float64) RGB { return RGB{v[0], v[1], v[2]} }
+
+func (c XYZ) Components() []float64 { return []float64{c.X, c.Y, c.Z} }
+
+func (XYZ) FromComponents(v []float64) XYZ { return XYZ{v[0], v[1], v[2]} }
+
+func (c Lab) Components() []float64 { return []float64{c.L, c.A, c.B} }
+
+func (Lab) FromComponents(v []float64) Lab { return Lab{v[0], v[1], v[2]} }
+
+func (c Oklab) Components() []float64 { return []float64{c.L, c.A, c.B} }
+
+func (Oklab) FromComponents(v []float64) Oklab { return Oklab{v[0], v[1], v[2]} }
+
+func (c HSL) Components() []float64 { return []float64{c.H, c.S, c.L} }
+
+func (HSL) FromComponents(v []float64) HSL { return HSL{v[0], v[1], v[2]} }
+
+func (HSL) hueComponent() int { return 0 }
+
+func (c Oklch) Components() []float64 { return []float64{c.L, c.C, c.H} }
+
+func (Oklch) FromComponents(v []float64) Oklch { return Oklch{v[0], v[1], v[2]} }
+
+func (Oklch) hueComponent() int { return 2 }
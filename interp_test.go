@@ -0,0 +1,59 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var (
+	_ Interpolatable[RGB]   = RGB{}
+	_ Interpolatable[HSL]   = HSL{}
+	_ Interpolatable[XYZ]   = XYZ{}
+	_ Interpolatable[Lab]   = Lab{}
+	_ Interpolatable[Oklab] = Oklab{}
+	_ Interpolatable[Oklch] = Oklch{}
+)
+
+func TestLerpComponents(t *testing.T) {
+	a, b := RGB{0.9, 0.2, 0.1}, RGB{0.1, 0.3, 0.8}
+	for _, x := range []float64{0, 0.3, 0.5, 1} {
+		if have, want := LerpComponents(a, b, x), Mix(a, b, x); !eqRGB(have, want) {
+			t.Errorf("RGB at %.1f: have %v, want %v", x, have, want)
+		}
+		la, lb := a.ToLab(), b.ToLab()
+		have, want := LerpComponents(la, lb, x), Lab{
+			la.L + (lb.L-la.L)*x,
+			la.A + (lb.A-la.A)*x,
+			la.B + (lb.B-la.B)*x,
+		}
+		if real.Diff(have.L, want.L) > epsilonF || real.Diff(have.A, want.A) > epsilonF || real.Diff(have.B, want.B) > epsilonF {
+			t.Errorf("Lab at %.1f: have %v, want %v", x, have, want)
+		}
+	}
+
+	// hues take the short way across red
+	ha, hb := HSL{0.9, 0.5, 0.4}, HSL{0.1, 0.5, 0.6}
+	for _, test := range []struct{ t, h float64 }{{0, 0.9}, {0.5, 0}, {1, 0.1}} {
+		have := LerpComponents(ha, hb, test.t)
+		if hueDistance(have.H, test.h) > 1e-9 {
+			t.Errorf("HSL at %.1f: have hue %f, want %f", test.t, have.H, test.h)
+		}
+	}
+	// complementary hues meet a quarter turn from each, fully saturated, as in MixHSL
+	ca, cb := HSL{0.1, 1, 0.5}, HSL{0.6, 1, 0.5}
+	for _, x := range []float64{0.25, 0.5, 0.75} {
+		have, want := LerpComponents(ca, cb, x), MixHSL(ca, cb, x)
+		if real.Diff(have.H, want.H) > epsilonF || real.Diff(have.S, want.S) > epsilonF || real.Diff(have.L, want.L) > epsilonF {
+			t.Errorf("complements at %.2f: have %v, want %v", x, have, want)
+		}
+	}
+	if mid := LerpComponents(ca, cb, 0.5); mid.S != 1 || real.Diff(hueDistance(mid.H, 0.1), 0.25) > epsilonF {
+		t.Errorf("complements: have %v, want saturation 1 a quarter turn from each", mid)
+	}
+
+	lch := LerpComponents(Oklch{0.5, 0.1, 0.95}, Oklch{0.7, 0.1, 0.15}, 0.5)
+	if hueDistance(lch.H, 0.05) > 1e-9 || real.Diff(lch.L, 0.6) > epsilonF {
+		t.Errorf("Oklch: have %v, want L 0.6 and hue 0.05", lch)
+	}
+}
@@ -0,0 +1,180 @@
+package color
+
+import "math"
+
+// XYZ is a color in the CIE 1931 XYZ space, with Y the luminance in [0, 1]
+type XYZ struct {
+	X, Y, Z float64
+}
+
+// Lab is a color in the CIELAB space, with L in [0, 100] and A, B roughly in [-128, 127]
+type Lab struct {
+	L, A, B float64
+}
+
+// WhitePoint is the XYZ of a reference white, normalized to a luminance of 1
+type WhitePoint XYZ
+
+var (
+	// D65 is noon daylight, the white point of sRGB and the default for CIELAB
+	D65 = WhitePoint{0.95047, 1, 1.08883}
+	// D50 is horizon light, the usual white point for print and ICC profiles
+	D50 = WhitePoint{0.96422, 1, 0.82521}
+)
+
+// The Bradford cone response matrix and its inverse
+var (
+	bradford = [3][3]float64{
+		{0.8951, 0.2664, -0.1614},
+		{-0.7502, 1.7135, 0.0367},
+		{0.0389, -0.0685, 1.0296},
+	}
+	bradfordInv = [3][3]float64{
+		{0.9869929055, -0.1470542564, 0.1599626517},
+		{0.4323052697, 0.5183602715, 0.0492912282},
+		{-0.0085286646, 0.0400428217, 0.9684866958},
+	}
+)
+
+// mul3 multiplies the column vector (x, y, z) by m
+func mul3(m [3][3]float64, x, y, z float64) (float64, float64, float64) {
+	return m[0][0]*x + m[0][1]*y + m[0][2]*z,
+		m[1][0]*x + m[1][1]*y + m[1][2]*z,
+		m[2][0]*x + m[2][1]*y + m[2][2]*z
+}
+
+// adapt carries c, seen under the white point from, over to how it would
+// appear under the white point to, using the Bradford transform
+func (c XYZ) adapt(from, to WhitePoint) XYZ {
+	if from == to {
+		return c
+	}
+	l, m, s := mul3(bradford, c.X, c.Y, c.Z)
+	lf, mf, sf := mul3(bradford, from.X, from.Y, from.Z)
+	lt, mt, st := mul3(bradford, to.X, to.Y, to.Z)
+	x, y, z := mul3(bradfordInv, l*lt/lf, m*mt/mf, s*st/sf)
+	return XYZ{x, y, z}
+}
+
+// ToXYZ converts c to CIE XYZ through the sRGB transfer function and primaries
+func (c RGB) ToXYZ() XYZ {
+	r, g, b := Linearize(c.R), Linearize(c.G), Linearize(c.B)
+	return XYZ{
+		0.4124564*r + 0.3575761*g + 0.1804375*b,
+		0.2126729*r + 0.7151522*g + 0.0721750*b,
+		0.0193339*r + 0.1191920*g + 0.9503041*b,
+	}
+}
+
+// ToRGB converts c to sRGB. Colors outside the sRGB gamut come back with
+// channels outside [0, 1], it's up to the caller to clamp them.
+func (c XYZ) ToRGB() RGB {
+	return RGB{
+		Delinearize(3.2404542*c.X - 1.5371385*c.Y - 0.4985314*c.Z),
+		Delinearize(-0.9692660*c.X + 1.8760108*c.Y + 0.0415560*c.Z),
+		Delinearize(0.0556434*c.X - 0.2040259*c.Y + 1.0572252*c.Z),
+	}
+}
+
+func (c XYZ) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
+
+// labF is the nonlinearity at the heart of CIELAB
+func labF(t float64) float64 {
+	const e = 6.0 / 29
+	if t > e*e*e {
+		return math.Cbrt(t)
+	}
+	return t/(3*e*e) + 4.0/29
+}
+
+// labFInv is the inverse of labF
+func labFInv(t float64) float64 {
+	const e = 6.0 / 29
+	if t > e {
+		return t * t * t
+	}
+	return 3 * e * e * (t - 4.0/29)
+}
+
+// ToLab converts c to CIELAB relative to the D65 white point
+func (c XYZ) ToLab() Lab {
+	return c.toLab(D65)
+}
+
+func (c XYZ) toLab(wp WhitePoint) Lab {
+	fx, fy, fz := labF(c.X/wp.X), labF(c.Y/wp.Y), labF(c.Z/wp.Z)
+	return Lab{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// ToXYZ converts c to CIE XYZ relative to the D65 white point
+func (c Lab) ToXYZ() XYZ {
+	return c.toXYZ(D65)
+}
+
+func (c Lab) toXYZ(wp WhitePoint) XYZ {
+	fy := (c.L + 16) / 116
+	return XYZ{
+		wp.X * labFInv(fy+c.A/500),
+		wp.Y * labFInv(fy),
+		wp.Z * labFInv(fy-c.B/200),
+	}
+}
+
+func (c RGB) ToLab() Lab {
+	return c.ToXYZ().ToLab()
+}
+
+func (c Lab) ToRGB() RGB {
+	return c.ToXYZ().ToRGB()
+}
+
+// ToLabWhite converts c to CIELAB relative to the white point wp,
+// adapting it from the D65 white of sRGB with the Bradford transform
+func (c RGB) ToLabWhite(wp WhitePoint) Lab {
+	return c.ToXYZ().adapt(D65, wp).toLab(wp)
+}
+
+// ToRGBWhite converts c, taken relative to the white point wp, to sRGB.
+// It is the inverse of RGB.ToLabWhite.
+func (c Lab) ToRGBWhite(wp WhitePoint) RGB {
+	return c.toXYZ(wp).adapt(wp, D65).ToRGB()
+}
+
+func (c Lab) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
+
+// IsNeutral reports whether c is a neutral or near neutral gray, with a CIELAB
+// chroma, sqrt(a² + b²), below chromaTolerance. Unlike the channel spread
+// StableHue goes by, chroma tracks how tinted a color looks, so warm and cool
+// grays pass a tolerance of a few units while pale but clearly tinted colors fail.
+func (c RGB) IsNeutral(chromaTolerance float64) bool {
+	lab := c.ToLab()
+	return math.Hypot(lab.A, lab.B) < chromaTolerance
+}
+
+// inGamut reports whether each channel of c lies in [0, 1], give or take rounding error
+func (c RGB) inGamut() bool {
+	return inUnitCube(c.R, c.G, c.B)
+}
+
+// fitGamut converts c to sRGB, desaturating it as little as possible to bring it
+// into gamut when needed. Lightness and hue are kept, which clamping the channels
+// of an out of gamut color would not do.
+func (c Lab) fitGamut() RGB {
+	if rgb := c.ToRGB(); rgb.inGamut() {
+		return rgb.Clamp()
+	}
+	lo, hi := 0.0, 1.0
+	for range 32 {
+		mid := (lo + hi) / 2
+		if (Lab{c.L, c.A * mid, c.B * mid}).ToRGB().inGamut() {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return Lab{c.L, c.A * lo, c.B * lo}.ToRGB().Clamp()
+}
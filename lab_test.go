@@ -0,0 +1,87 @@
+package color
+
+import (
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var (
+	_ color.Color = XYZ{}
+	_ color.Color = Lab{}
+)
+
+func TestLabWhite(t *testing.T) {
+	const epsilon = 1e-5 // the sRGB matrices carry 7 significant digits, and Delinearize magnifies their error near 0
+	for range nTrials {
+		c := RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		t.Run(c.ToHTML(), func(t *testing.T) {
+			if have, want := c.ToLabWhite(D65), c.ToLab(); have != want {
+				t.Errorf("D65: have %v, want %v", have, want)
+			}
+			d50, d65 := c.ToLabWhite(D50), c.ToLab()
+			if d50 == d65 {
+				t.Errorf("D50 and D65 agree on %v", d50)
+			}
+			have := d50.ToRGBWhite(D50)
+			if real.Diff(have.R, c.R) > epsilon || real.Diff(have.G, c.G) > epsilon || real.Diff(have.B, c.B) > epsilon {
+				t.Errorf("D50 round trip: have %v, want %v", have, c)
+			}
+		})
+	}
+
+	// adaptation takes white to white
+	w := white.ToLabWhite(D50)
+	if real.Diff(w.L, 100) > 1e-3 || real.Abs(w.A) > 1e-3 || real.Abs(w.B) > 1e-3 {
+		t.Errorf("white under D50: have %v, want {100 0 0}", w)
+	}
+	if red50, red65 := (RGB{1, 0, 0}).ToLabWhite(D50), (RGB{1, 0, 0}).ToLab(); real.Diff(red50.B, red65.B) < 1 {
+		t.Errorf("red: D50 %v barely differs from D65 %v", red50, red65)
+	}
+}
+
+func TestIsNeutral(t *testing.T) {
+	const tolerance = 5
+	warmGray, paleYellow := RGB{0.52, 0.5, 0.47}, RGB{1, 1, 0.8}
+	if _, ok := warmGray.StableHue(); !ok {
+		t.Fatalf("%s: want a channel spread that counts as tinted", warmGray.ToHTML())
+	}
+	for _, test := range []struct {
+		c    RGB
+		want bool
+	}{
+		{black, true},
+		{white, true},
+		{RGB{0.5, 0.5, 0.5}, true},
+		{warmGray, true},
+		{paleYellow, false},
+		{RGB{1, 0, 0}, false},
+	} {
+		if have := test.c.IsNeutral(tolerance); have != test.want {
+			t.Errorf("%s: have %v, want %v", test.c.ToHTML(), have, test.want)
+		}
+	}
+}
+
+func TestLabRoundTrip(t *testing.T) {
+	const epsilon = 1e-5 // the sRGB matrices carry 7 significant digits
+	for range nTrials {
+		want := RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		t.Run(want.ToHTML(), func(t *testing.T) {
+			have := want.ToXYZ().ToLab().ToXYZ().ToRGB()
+			if real.Diff(have.R, want.R) > epsilon || real.Diff(have.G, want.G) > epsilon || real.Diff(have.B, want.B) > epsilon {
+				t.Errorf("have %v, want %v", have, want)
+			}
+		})
+	}
+
+	// the reference white is the top of the lightness axis
+	if have := XYZ(D65).ToLab(); real.Diff(have.L, 100) > epsilonF || real.Abs(have.A) > epsilonF || real.Abs(have.B) > epsilonF {
+		t.Errorf("D65: have %v, want {100 0 0}", have)
+	}
+	if have := white.ToXYZ(); real.Diff(have.X, D65.X) > 1e-4 || real.Diff(have.Y, D65.Y) > 1e-4 || real.Diff(have.Z, D65.Z) > 1e-4 {
+		t.Errorf("white: have %v, want %v", have, D65)
+	}
+}
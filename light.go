@@ -0,0 +1,147 @@
+package color
+
+import "math"
+
+// The limits of visible light, in nanometres
+const (
+	minWavelength = 380
+	maxWavelength = 780
+)
+
+// FromWavelength approximates the color of monochromatic light of the given
+// wavelength in nanometres, after Dan Bruton's well known piecewise fit.
+// Light outside the visible range of 380 to 780 nm is black, and intensity
+// tapers off toward either end of it as the eye's sensitivity does.
+func FromWavelength(nm float64) RGB {
+	var r, g, b float64
+	switch {
+	case nm < minWavelength || nm > maxWavelength:
+		return RGB{}
+	case nm < 440:
+		r, b = (440-nm)/(440-380), 1
+	case nm < 490:
+		g, b = (nm-440)/(490-440), 1
+	case nm < 510:
+		g, b = 1, (510-nm)/(510-490)
+	case nm < 580:
+		r, g = (nm-510)/(580-510), 1
+	case nm < 645:
+		r, g = 1, (645-nm)/(645-580)
+	default:
+		r = 1
+	}
+
+	f := 1.0
+	switch {
+	case nm < 420:
+		f = 0.3 + 0.7*(nm-minWavelength)/(420-minWavelength)
+	case nm > 700:
+		f = 0.3 + 0.7*(maxWavelength-nm)/(maxWavelength-700)
+	}
+	const gamma = 0.8
+	return RGB{math.Pow(r*f, gamma), math.Pow(g*f, gamma), math.Pow(b*f, gamma)}
+}
+
+// NearestSpectral returns the spectral color, as given by FromWavelength,
+// that is nearest to c by CIEDE2000, along with its wavelength in nanometres.
+// Wavelengths are searched in whole nanometres.
+func (c RGB) NearestSpectral() (RGB, float64) {
+	lab := c.ToLab()
+	best, bestNM, dist := RGB{}, 0.0, math.Inf(1)
+	for nm := float64(minWavelength); nm <= maxWavelength; nm++ {
+		s := FromWavelength(nm)
+		if d := ciede2000(lab, s.ToLab()); d < dist {
+			best, bestNM, dist = s, nm, d
+		}
+	}
+	return best, bestNM
+}
+
+// maxDuv is how far, in CIE 1960 uv, a chromaticity may lie from the
+// Planckian locus and still be given a correlated color temperature
+const maxDuv = 0.05
+
+// The span of temperatures in kelvin that the approximations below hold over
+const (
+	minCCT = 1000
+	maxCCT = 25000
+)
+
+// planckian returns the CIE 1960 uv chromaticity of a black body at k kelvin,
+// after Krystek's rational approximation
+func planckian(k float64) (u, v float64) {
+	u = (0.860117757 + 1.54118254e-4*k + 1.28641212e-7*k*k) / (1 + 8.42420235e-4*k + 7.08145163e-7*k*k)
+	v = (0.317398726 + 4.22806245e-5*k + 4.20481691e-8*k*k) / (1 - 2.89741816e-5*k + 1.61456053e-7*k*k)
+	return
+}
+
+// CorrelatedColorTemperature returns the temperature in kelvin of the black
+// body whose light looks most like c, by McCamy's approximation. ok is false
+// for black, for colors too saturated to lie near the black body curve, and
+// for temperatures outside roughly 1000 to 25000 K.
+func CorrelatedColorTemperature(c RGB) (k float64, ok bool) {
+	xyz := c.ToXYZ()
+	sum := xyz.X + xyz.Y + xyz.Z
+	if sum <= 0 {
+		return 0, false
+	}
+	x, y := xyz.X/sum, xyz.Y/sum
+	n := (x - 0.3320) / (0.1858 - y)
+	k = 449*n*n*n + 3525*n*n + 6823.3*n + 5520.33
+	if k < minCCT || k > maxCCT {
+		return 0, false
+	}
+
+	denom := xyz.X + 15*xyz.Y + 3*xyz.Z
+	u, v := 4*xyz.X/denom, 6*xyz.Y/denom
+	pu, pv := planckian(k)
+	if math.Hypot(u-pu, v-pv) > maxDuv {
+		return 0, false
+	}
+	return k, true
+}
+
+// TemperatureDelta returns how much warmer a is than b, as the difference in
+// kelvin of b's correlated color temperature less a's. It is NaN when either
+// color has no correlated color temperature.
+func TemperatureDelta(a, b RGB) float64 {
+	ka, okA := CorrelatedColorTemperature(a)
+	kb, okB := CorrelatedColorTemperature(b)
+	if !okA || !okB {
+		return math.NaN()
+	}
+	return kb - ka
+}
+
+// The span of temperatures in kelvin that FromKelvin's fit covers
+const (
+	minKelvin = 1000
+	maxKelvin = 40000
+)
+
+// FromKelvin approximates the color of a black body glowing at k kelvin, after
+// Tanner Helland's fit to Mitchell Charity's black body data. Temperatures are
+// clamped into the 1000 to 40000 K the fit covers. Around 6500 K, the
+// temperature of daylight, the result is close to white; lower temperatures
+// run to orange and higher ones to blue. A NaN temperature, which has no side
+// of the range to clamp to, gives black.
+func FromKelvin(k float64) RGB {
+	if math.IsNaN(k) {
+		return RGB{}
+	}
+	t := min(max(k, minKelvin), maxKelvin) / 100
+	r, g, b := 255.0, 255.0, 255.0
+	if t <= 66 {
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+		switch {
+		case t <= 19:
+			b = 0
+		case t < 66:
+			b = 138.5177312231*math.Log(t-10) - 305.0447927307
+		}
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+	return RGB{r / 255, g / 255, b / 255}.Clamp()
+}
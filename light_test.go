@@ -0,0 +1,115 @@
+package color
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFromWavelength(t *testing.T) {
+	for _, nm := range []float64{300, 379, 781, 1000} {
+		if have := FromWavelength(nm); have != black {
+			t.Errorf("%4.0f nm: have %v, want black", nm, have)
+		}
+	}
+	for _, test := range []struct {
+		nm   float64
+		want RGB
+	}{
+		{440, RGB{0, 0, 1}},
+		{510, RGB{0, 1, 0}},
+		{580, RGB{1, 1, 0}},
+		{645, RGB{1, 0, 0}},
+	} {
+		if have := FromWavelength(test.nm); !eqRGB(have, test.want) {
+			t.Errorf("%4.0f nm: have %v, want %v", test.nm, have, test.want)
+		}
+	}
+}
+
+func TestNearestSpectral(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		c      RGB
+		lo, hi float64
+	}{
+		{"green", RGB{0.1, 0.9, 0.1}, 505, 555},
+		{"red", RGB{0.9, 0.05, 0.05}, 620, 780},
+		{"blue", RGB{0.05, 0.1, 0.95}, 430, 480},
+		{"yellow", RGB{1, 1, 0}, 570, 590},
+	} {
+		s, nm := test.c.NearestSpectral()
+		if nm < test.lo || nm > test.hi {
+			t.Errorf("%s: have %.0f nm, want within [%.0f, %.0f]", test.name, nm, test.lo, test.hi)
+		}
+		if s != FromWavelength(nm) {
+			t.Errorf("%s: have %v, want the color of %.0f nm", test.name, s, nm)
+		}
+	}
+}
+
+func TestCorrelatedColorTemperature(t *testing.T) {
+	// sRGB white is D65
+	if k, ok := CorrelatedColorTemperature(white); !ok || math.Abs(k-6504) > 50 {
+		t.Errorf("white: have %.0f K %v, want about 6504 K", k, ok)
+	}
+	for _, c := range []RGB{black, {1, 0, 0}, {0, 1, 0}, {0.2, 0.2, 1}, {1, 0, 1}} {
+		if k, ok := CorrelatedColorTemperature(c); ok {
+			t.Errorf("%s: have %.0f K, want none", c.ToHTML(), k)
+		}
+	}
+}
+
+func TestTemperatureDelta(t *testing.T) {
+	warm, cool := RGB{1, 0.92, 0.82}, RGB{0.88, 0.93, 1}
+	if d := TemperatureDelta(warm, cool); d < 2000 {
+		t.Errorf("warm against cool: have %.0f K, want warm to be well below cool", d)
+	}
+	if d, e := TemperatureDelta(warm, cool), TemperatureDelta(cool, warm); d != -e {
+		t.Errorf("not antisymmetric: %.0f and %.0f", d, e)
+	}
+	if d := TemperatureDelta(warm, warm); d != 0 {
+		t.Errorf("self: have %f, want 0", d)
+	}
+	if d := TemperatureDelta(warm, RGB{1, 0, 0}); !math.IsNaN(d) {
+		t.Errorf("saturated: have %f, want NaN", d)
+	}
+}
+
+func TestFromKelvin(t *testing.T) {
+	if c := FromKelvin(6500); c.R < 0.97 || c.G < 0.97 || c.B < 0.97 || !c.IsNeutral(5) {
+		t.Errorf("6500K: have %v, want near white", c)
+	}
+	if c := FromKelvin(66 * 100); c != white {
+		t.Errorf("6600K: have %v, want %v", c, white)
+	}
+
+	// warmer light is redder and cooler light bluer
+	prev := FromKelvin(minKelvin)
+	for k := 1500.0; k <= maxKelvin; k += 500 {
+		c := FromKelvin(k)
+		if !c.inGamut() {
+			t.Errorf("%vK: have %v, out of gamut", k, c)
+		}
+		if c.B/c.R < prev.B/prev.R {
+			t.Errorf("%vK: have %v, less blue than %v", k, c, prev)
+		}
+		prev = c
+	}
+	if c := FromKelvin(1900); c.B != 0 || c.R != 1 {
+		t.Errorf("1900K: have %v, want full red and no blue", c)
+	}
+
+	for _, test := range []struct{ in, want float64 }{
+		{0, minKelvin},
+		{-300, minKelvin},
+		{100000, maxKelvin},
+		{math.Inf(1), maxKelvin},
+	} {
+		if have, want := FromKelvin(test.in), FromKelvin(test.want); have != want {
+			t.Errorf("%vK: have %v, want %v", test.in, have, want)
+		}
+	}
+	if c := FromKelvin(math.NaN()); c != black {
+		t.Errorf("NaN: have %v, want %v", c, black)
+	}
+}
@@ -0,0 +1,80 @@
+package color
+
+import "math"
+
+// wrapHue wraps h into [0, 1)
+func wrapHue(h float64) float64 {
+	return h - math.Floor(h)
+}
+
+// LerpHue interpolates from hue a to hue b, both in [0, 1], along the shorter
+// arc of the color wheel. The result is wrapped into [0, 1), so going from
+// 0.9 to 0.1 passes through 0 rather than sweeping back across 0.5.
+func LerpHue(a, b, t float64) float64 {
+	d := b - a
+	switch {
+	case d > 0.5:
+		d -= 1
+	case d < -0.5:
+		d += 1
+	}
+	return wrapHue(a + d*t)
+}
+
+// hueComponents maps a hue in [0, 1) to a point on the unit circle, for
+// averaging hues without 0.95 and 0.05 meeting at cyan
+func hueComponents(h float64) (x, y float64) {
+	return math.Cos(2 * math.Pi * h), math.Sin(2 * math.Pi * h)
+}
+
+// hueFromComponents is the inverse of hueComponents
+func hueFromComponents(x, y float64) float64 {
+	return wrapHue(math.Atan2(y, x) / (2 * math.Pi))
+}
+
+// lerp interpolates from a to b, giving exactly a at t = 0 and exactly b at t = 1
+func lerp(a, b, t float64) float64 {
+	return a*(1-t) + b*t
+}
+
+// Mix linearly interpolates each channel from a to b.
+// t is clamped into [0, 1], so 0 and below give exactly a and 1 and above
+// give exactly b.
+func Mix(a, b RGB, t float64) RGB {
+	t = clamp01(t)
+	return RGB{lerp(a.R, b.R, t), lerp(a.G, b.G, t), lerp(a.B, b.B, t)}
+}
+
+// MixHSL interpolates from a to b in HSL, taking the hue along the shorter arc
+// of the wheel as LerpHue does, so midpoints keep their color rather than
+// turning muddy. A gray (zero saturation) takes on the other color's hue.
+// t is clamped into [0, 1], so 0 and below give exactly a and 1 and above
+// give exactly b.
+func MixHSL(a, b HSL, t float64) HSL {
+	switch t = clamp01(t); {
+	case t == 0:
+		return a
+	case t == 1:
+		return b
+	}
+	ha, hb := a.H, b.H
+	switch {
+	case a.S == 0:
+		ha = hb
+	case b.S == 0:
+		hb = ha
+	}
+	return HSL{LerpHue(ha, hb, t), lerp(a.S, b.S, t), lerp(a.L, b.L, t)}
+}
+
+// mixLab interpolates from a to b in CIELAB, which changes more evenly to the eye
+// than interpolating the RGB channels. Results outside sRGB are brought into
+// gamut at the same lightness and hue.
+func mixLab(a, b RGB, t float64) RGB {
+	la, lb := a.ToLab(), b.ToLab()
+	return Lab{
+		la.L + (lb.L-la.L)*t,
+		la.A + (lb.A-la.A)*t,
+		la.B + (lb.B-la.B)*t,
+	}.fitGamut()
+}
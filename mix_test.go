@@ -0,0 +1,86 @@
+package color
+
+import (
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestLerpHue(t *testing.T) {
+	for _, test := range []struct{ a, b, t, want float64 }{
+		{0.9, 0.1, 0, 0.9},
+		{0.9, 0.1, 0.25, 0.95},
+		{0.9, 0.1, 0.5, 0},
+		{0.9, 0.1, 0.75, 0.05},
+		{0.9, 0.1, 1, 0.1},
+		{0.1, 0.9, 0.5, 0},
+		{0.1, 0.6, 0.5, 0.35},
+		{0.1, 0.6, 1, 0.6},
+		{0.2, 0.4, 0.5, 0.3},
+	} {
+		if have := LerpHue(test.a, test.b, test.t); real.Diff(have, test.want) > epsilonF {
+			t.Errorf("%.2f -> %.2f at %.2f: have %f, want %f", test.a, test.b, test.t, have, test.want)
+		}
+	}
+}
+
+func TestMix(t *testing.T) {
+	a, b := RGB{0.1, 0.2, 0.7}, RGB{0.3, 0.9, 0.4}
+	for _, test := range []struct {
+		t    float64
+		want RGB
+	}{
+		{-1, a},
+		{0, a},
+		{0.5, RGB{0.2, 0.55, 0.55}},
+		{1, b},
+		{2, b},
+	} {
+		have := Mix(a, b, test.t)
+		if test.t <= 0 || test.t >= 1 {
+			if have != test.want {
+				t.Errorf("%.1f: have %v, want exactly %v", test.t, have, test.want)
+			}
+		} else if !eqRGB(have, test.want) {
+			t.Errorf("%.1f: have %v, want %v", test.t, have, test.want)
+		}
+	}
+}
+
+func TestMixHSL(t *testing.T) {
+	a, b := HSL{0.9, 0.8, 0.4}, HSL{0.1, 0.4, 0.6}
+	if have := MixHSL(a, b, 0); have != a {
+		t.Errorf("0: have %v, want %v", have, a)
+	}
+	if have := MixHSL(a, b, 1.5); have != b {
+		t.Errorf("1.5: have %v, want %v", have, b)
+	}
+	// the hue crosses red rather than sweeping through cyan
+	have, want := MixHSL(a, b, 0.5), HSL{0, 0.6, 0.5}
+	if hueDistance(have.H, want.H) > epsilonF || real.Diff(have.S, want.S) > epsilonF || real.Diff(have.L, want.L) > epsilonF {
+		t.Errorf("0.5: have %v, want %v", have, want)
+	}
+
+	// a gray fades into the other color's hue
+	gray := HSL{0.5, 0, 0.5}
+	for _, x := range []float64{0.25, 0.5, 0.75} {
+		if have := MixHSL(gray, b, x); real.Diff(have.H, b.H) > epsilonF {
+			t.Errorf("gray at %.2f: have hue %f, want %f", x, have.H, b.H)
+		}
+	}
+}
+
+func TestMixLab(t *testing.T) {
+	// halfway from red to blue lies outside sRGB, and comes back in at the
+	// midpoint's lightness and hue
+	red, blue := RGB{1, 0, 0}.ToLab(), RGB{0, 0, 1}.ToLab()
+	want := Lab{(red.L + blue.L) / 2, (red.A + blue.A) / 2, (red.B + blue.B) / 2}
+	if want.ToRGB().inGamut() {
+		t.Fatalf("%v is in gamut, pick another pair", want)
+	}
+	have := mixLab(RGB{1, 0, 0}, RGB{0, 0, 1}, 0.5)
+	lab := have.ToLab()
+	if !have.inGamut() || real.Diff(lab.L, want.L) > 0.1 || real.Diff(hueDeg(lab.B, lab.A), hueDeg(want.B, want.A)) > 1 {
+		t.Errorf("have %v, want L* %.2f and hue %.1f°", lab, want.L, hueDeg(want.B, want.A))
+	}
+}
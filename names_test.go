@@ -0,0 +1,89 @@
+package color
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	const want = "darkslategray"
+	for _, name := range []string{
+		"darkslategray",
+		"Dark Slate Gray",
+		"DARK_SLATE_GRAY",
+		"dark-slate-gray",
+		"  DarkSlateGray ",
+	} {
+		if have := normalizeName(name); have != want {
+			t.Errorf("%q: have %q, want %q", name, have, want)
+		}
+	}
+	if have := normalizeName("Not A Color"); have == want {
+		t.Errorf("distinct names normalized alike: %q", have)
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"rebeccapurple", "663399"},
+		{"RebeccaPurple", "663399"},
+		{"Dark Slate Gray", "2f4f4f"},
+		{"white", "ffffff"},
+		{"Cornflower-Blue", "6495ed"},
+	} {
+		have, err := ByName(test.name)
+		if err != nil || have.ToHTML() != test.want {
+			t.Errorf("%q: have %s %v, want %s", test.name, have.ToHTML(), err, test.want)
+		}
+	}
+	for _, name := range []string{"", "notacolor", "transparent", "currentcolor"} {
+		if c, err := ByName(name); err == nil {
+			t.Errorf("%q: have %v, want an error", name, c)
+		}
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	for name := range cssNames {
+		c, _ := ByName(name)
+		have, ok := NameOf(c)
+		if !ok {
+			t.Errorf("%s: have no name", name)
+			continue
+		}
+		if again, _ := ByName(have); again != c {
+			t.Errorf("%s: named %s, which is %v", name, have, again)
+		}
+	}
+	for _, test := range []struct {
+		c    RGB
+		want string
+	}{
+		{RGB{0, 1, 1}, "aqua"},
+		{RGB{0.5, 0.5, 0.5}, ""}, // between two bytes
+		{RGB{0x80 / 255.0, 0x80 / 255.0, 0x80 / 255.0}, "gray"},
+		{RGB{0.1, 0.2, 0.3}, ""},
+	} {
+		if have, ok := NameOf(test.c); have != test.want || ok != (test.want != "") {
+			t.Errorf("%v: have %q %v, want %q", test.c, have, ok, test.want)
+		}
+	}
+}
+
+func TestCSSNamesComplete(t *testing.T) {
+	// CSS Color 4 has 148 named colors, not counting transparent and currentColor
+	if have := len(cssNames); have != 148 {
+		t.Errorf("have %d names, want 148", have)
+	}
+	for name, want := range map[string]string{
+		"rebeccapurple":        "663399",
+		"darkslategrey":        "2f4f4f",
+		"lightgoldenrodyellow": "fafad2",
+		"mediumspringgreen":    "00fa9a",
+		"grey":                 "808080",
+		"lightslategrey":       "778899",
+	} {
+		if have, err := ByName(name); err != nil || have.ToHTML() != want {
+			t.Errorf("%s: have %s %v, want %s", name, have.ToHTML(), err, want)
+		}
+	}
+}
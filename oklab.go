@@ -0,0 +1,87 @@
+package color
+
+import "math"
+
+// Oklab is a color in Björn Ottosson's Oklab space, with L in [0, 1]
+// and A, B roughly in [-0.4, 0.4]
+type Oklab struct {
+	L, A, B float64
+}
+
+// Oklch is the polar form of Oklab, with L in [0, 1], C roughly in [0, 0.4],
+// and the hue H in [0, 1) like the rest of the package's hues
+type Oklch struct {
+	L, C, H float64
+}
+
+func (c RGB) ToOklab() Oklab {
+	r, g, b := Linearize(c.R), Linearize(c.G), Linearize(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return Oklab{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// ToRGB converts c to sRGB. Colors outside the sRGB gamut come back with
+// channels outside [0, 1], it's up to the caller to clamp them.
+func (c Oklab) ToRGB() RGB {
+	l := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	m := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	s := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return RGB{
+		Delinearize(4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		Delinearize(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		Delinearize(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s),
+	}
+}
+
+func (c Oklab) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
+
+func (c Oklab) ToOklch() Oklch {
+	return Oklch{c.L, math.Hypot(c.A, c.B), wrapHue(math.Atan2(c.B, c.A) / (2 * math.Pi))}
+}
+
+func (c Oklch) ToOklab() Oklab {
+	theta := 2 * math.Pi * c.H
+	return Oklab{c.L, c.C * math.Cos(theta), c.C * math.Sin(theta)}
+}
+
+func (c RGB) ToOklch() Oklch {
+	return c.ToOklab().ToOklch()
+}
+
+// ToRGB converts c to sRGB, see Oklab.ToRGB
+func (c Oklch) ToRGB() RGB {
+	return c.ToOklab().ToRGB()
+}
+
+func (c Oklch) RGBA() (r, g, b, a uint32) {
+	return c.ToRGB().RGBA()
+}
+
+// fitGamut converts c to sRGB, reducing its chroma as little as possible to
+// bring it into gamut when needed, as CSS Color 4 does
+func (c Oklch) fitGamut() RGB {
+	c.L = clamp01(c.L)
+	if rgb := c.ToRGB(); rgb.inGamut() {
+		return rgb.Clamp()
+	}
+	lo, hi := 0.0, c.C
+	for range 32 {
+		mid := (lo + hi) / 2
+		if (Oklch{c.L, mid, c.H}).ToRGB().inGamut() {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	c.C = lo
+	return c.ToRGB().Clamp()
+}
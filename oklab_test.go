@@ -0,0 +1,33 @@
+package color
+
+import (
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var (
+	_ color.Color = Oklab{}
+	_ color.Color = Oklch{}
+)
+
+func TestRGBtoOklchtoRGB(t *testing.T) {
+	const epsilon = 1e-5 // the published matrices carry about 7 significant digits
+	for range nTrials {
+		want := RGB{rand.Float64(), rand.Float64(), rand.Float64()}
+		t.Run(want.ToHTML(), func(t *testing.T) {
+			have := want.ToOklch().ToRGB()
+			if real.Diff(have.R, want.R) > epsilon || real.Diff(have.G, want.G) > epsilon || real.Diff(have.B, want.B) > epsilon {
+				t.Errorf("have %v, want %v", have, want)
+			}
+		})
+	}
+
+	// white sits at the top of the achromatic axis
+	w := white.ToOklab()
+	if real.Diff(w.L, 1) > 1e-4 || real.Abs(w.A) > 1e-4 || real.Abs(w.B) > 1e-4 {
+		t.Errorf("white: have %v, want {1 0 0}", w)
+	}
+}
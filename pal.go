@@ -0,0 +1,61 @@
+package color
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Microsoft RIFF palettes wrap a LOGPALETTE, a little endian version and count
+// followed by red, green, blue, and flags bytes per entry, in the data chunk
+// of a RIFF file of form type "PAL "
+const (
+	riffHeaderSize = 12
+	riffChunkSize  = 8
+	palEntrySize   = 4
+	palMaxSize     = 1 << 20
+)
+
+// LoadRIFFPAL reads a Microsoft RIFF palette (.pal), as used by Windows.
+// Chunks other than data are skipped and entry flags are ignored.
+func LoadRIFFPAL(r io.Reader) (Palette, error) {
+	buf, err := io.ReadAll(io.LimitReader(r, palMaxSize))
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) < riffHeaderSize || !bytes.Equal(buf[:4], []byte("RIFF")) || !bytes.Equal(buf[8:12], []byte("PAL ")) {
+		return nil, errors.New("pal: not a RIFF palette")
+	}
+
+	for rest := buf[riffHeaderSize:]; len(rest) >= riffChunkSize; {
+		id, size := string(rest[:4]), int(binary.LittleEndian.Uint32(rest[4:8]))
+		rest = rest[riffChunkSize:]
+		if size > len(rest) {
+			return nil, fmt.Errorf("pal: %q chunk runs past the end of the file", id)
+		}
+		if id != "data" {
+			// chunks are padded to an even length
+			rest = rest[min(size+size%2, len(rest)):]
+			continue
+		}
+
+		data := rest[:size]
+		if len(data) < 4 {
+			return nil, errors.New("pal: data chunk is too short")
+		}
+		n := int(binary.LittleEndian.Uint16(data[2:4]))
+		entries := data[4:]
+		if len(entries) < n*palEntrySize {
+			return nil, fmt.Errorf("pal: data chunk holds %d bytes of entries, want %d for %d colors", len(entries), n*palEntrySize, n)
+		}
+		p := make(Palette, n)
+		for i := range p {
+			e := entries[i*palEntrySize:]
+			p[i] = RGB{}.constructor(e[0], e[1], e[2])
+		}
+		return p, nil
+	}
+	return nil, errors.New("pal: no data chunk")
+}
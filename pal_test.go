@@ -0,0 +1,45 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+// a three color RIFF palette with a chunk ahead of its data, as some writers add
+var riffPAL = []byte{
+	'R', 'I', 'F', 'F', 40, 0, 0, 0, 'P', 'A', 'L', ' ',
+	'I', 'N', 'F', 'O', 3, 0, 0, 0, 'a', 'b', 'c', 0, // padded to an even length
+	'd', 'a', 't', 'a', 16, 0, 0, 0,
+	0x00, 0x03, 3, 0, // version 0x300, 3 entries
+	0xff, 0x00, 0x00, 0x00,
+	0x33, 0x66, 0x99, 0x00,
+	0xff, 0xff, 0xff, 0x04,
+}
+
+func TestLoadRIFFPAL(t *testing.T) {
+	want := Palette{{1, 0, 0}, {0x33 / 255.0, 0x66 / 255.0, 0x99 / 255.0}, white}
+	have, err := LoadRIFFPAL(bytes.NewReader(riffPAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d", len(have), len(want))
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d: have %s, want %s", i, have[i].ToHTML(), want[i].ToHTML())
+		}
+	}
+
+	for name, blob := range map[string][]byte{
+		"empty":     nil,
+		"not riff":  append([]byte("RIFX"), riffPAL[4:]...),
+		"not pal":   append(bytes.Clone(riffPAL[:8]), append([]byte("WAVE"), riffPAL[12:]...)...),
+		"truncated": riffPAL[:len(riffPAL)-2],
+		"no data":   riffPAL[:24],
+	} {
+		if p, err := LoadRIFFPAL(bytes.NewReader(blob)); err == nil {
+			t.Errorf("%s: have %v, want an error", name, p)
+		}
+	}
+}
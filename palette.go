@@ -0,0 +1,169 @@
+package color
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/bits"
+)
+
+// Palette is an ordered set of colors
+type Palette []RGB
+
+// BitDepth returns the fewest bits per index that can address every color
+// in p, that is the smallest n such that 2^n >= len(p)
+func (p Palette) BitDepth() int {
+	if len(p) < 2 {
+		return 0
+	}
+	return bits.Len(uint(len(p) - 1))
+}
+
+// Index returns the index of the color in p nearest to c by CIEDE2000, as
+// color.Palette's Index does by RGB distance. Alpha is ignored.
+// It returns -1 for an empty palette.
+func (p Palette) Index(c color.Color) int {
+	_, i := Nearest(rgbModel(c).(RGB), p)
+	return i
+}
+
+// Convert returns the color in p nearest to c, see Palette.Index.
+// It returns nil for an empty palette.
+func (p Palette) Convert(c color.Color) color.Color {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[p.Index(c)]
+}
+
+// ColorPalette returns p as a color.Palette, for image.NewPaletted and the
+// GIF encoder
+func (p Palette) ColorPalette() color.Palette {
+	out := make(color.Palette, len(p))
+	for i, c := range p {
+		out[i] = c
+	}
+	return out
+}
+
+// TextColors returns the most legible text color, black or white, for each
+// background in p as chosen by BestTextColor. The result lines up index for index with p.
+func (p Palette) TextColors() Palette {
+	out := make(Palette, len(p))
+	for i, c := range p {
+		out[i] = BestTextColor(c)
+	}
+	return out
+}
+
+// Resample treats p as the evenly spaced stops of a gradient and returns n
+// colors sampled evenly along it, end to end, interpolating in CIELAB.
+// Growing a palette fills in between its colors and shrinking it picks out
+// colors along the way. A single color is returned for n of 1.
+func (p Palette) Resample(n int) Palette {
+	if n <= 0 || len(p) == 0 {
+		return nil
+	}
+	out := make(Palette, n)
+	for i := range out {
+		if n == 1 || len(p) == 1 {
+			out[i] = p[0]
+			continue
+		}
+		pos := float64(i) * float64(len(p)-1) / float64(n-1)
+		j := int(pos)
+		if pos == float64(j) {
+			// on a stop, so skip the lossy trip through CIELAB
+			out[i] = p[j]
+			continue
+		}
+		out[i] = mixLab(p[j], p[j+1], pos-float64(j))
+	}
+	return out
+}
+
+// BlendPalettes interpolates from a to b color by color in CIELAB, as a theme
+// variant between the two. t is clamped into [0, 1], so 0 and below give a copy
+// of a and 1 and above a copy of b. The palettes must be the same length.
+func BlendPalettes(a, b Palette, t float64) (Palette, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("palette lengths differ: %d and %d", len(a), len(b))
+	}
+	t = clamp01(t)
+	out := make(Palette, len(a))
+	for i := range out {
+		switch t {
+		case 0:
+			out[i] = a[i]
+		case 1:
+			out[i] = b[i]
+		default:
+			out[i] = mixLab(a[i], b[i], t)
+		}
+	}
+	return out, nil
+}
+
+// Saturation and lightness of generated categorical colors
+const (
+	categoricalS = 0.65
+	categoricalL = 0.5
+)
+
+// CategoricalAvoiding returns n distinct colors for categorical data whose hues
+// are spread evenly around the wheel, skipping the band of width avoidWidth
+// centered on avoidHue. Both are in the [0, 1] hue scale, so to keep clear of
+// red (reserved for errors, say) one could pass HueRed and 1/6.
+func CategoricalAvoiding(n int, avoidHue, avoidWidth float64) []RGB {
+	if n <= 0 {
+		return nil
+	}
+	avoidWidth = clamp01(avoidWidth)
+	start := avoidHue + avoidWidth/2
+	span := 1 - avoidWidth
+
+	out := make([]RGB, n)
+	for i := range out {
+		h := wrapHue(start + span*(float64(i)+0.5)/float64(n))
+		out[i] = HSL{h, categoricalS, categoricalL}.ToRGB()
+	}
+	return out
+}
+
+// channel8 converts a channel in [0, 1] to the nearest byte, clamping out of range values
+func channel8(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 0xff))
+}
+
+// ChannelMean returns the mean of each channel over colors, as an RGB.
+// It is the zero RGB for no colors.
+func ChannelMean(colors []RGB) RGB {
+	if len(colors) == 0 {
+		return RGB{}
+	}
+	var sum RGB
+	for _, c := range colors {
+		sum.R += c.R
+		sum.G += c.G
+		sum.B += c.B
+	}
+	n := float64(len(colors))
+	return RGB{sum.R / n, sum.G / n, sum.B / n}
+}
+
+// ChannelStdDev returns the population standard deviation of each channel over
+// colors, as an RGB. It is the zero RGB for no colors.
+func ChannelStdDev(colors []RGB) RGB {
+	if len(colors) == 0 {
+		return RGB{}
+	}
+	mean := ChannelMean(colors)
+	var sq RGB
+	for _, c := range colors {
+		sq.R += (c.R - mean.R) * (c.R - mean.R)
+		sq.G += (c.G - mean.G) * (c.G - mean.G)
+		sq.B += (c.B - mean.B) * (c.B - mean.B)
+	}
+	n := float64(len(colors))
+	return RGB{math.Sqrt(sq.R / n), math.Sqrt(sq.G / n), math.Sqrt(sq.B / n)}
+}
@@ -0,0 +1,193 @@
+package color
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestBitDepth(t *testing.T) {
+	for _, test := range []struct{ size, want int }{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{3, 2},
+		{4, 2},
+		{5, 3},
+		{16, 4},
+		{17, 5},
+		{200, 8},
+		{256, 8},
+		{257, 9},
+	} {
+		if have := make(Palette, test.size).BitDepth(); have != test.want {
+			t.Errorf("%3d colors: have %d, want %d", test.size, have, test.want)
+		}
+	}
+}
+
+func TestTextColors(t *testing.T) {
+	p := Palette{
+		{1, 1, 1},       // white
+		{0, 0, 0.3},     // navy
+		{1, 1, 0.6},     // pale yellow
+		{0.2, 0.2, 0.2}, // charcoal
+		{0, 0.5, 0},     // green
+	}
+	want := Palette{black, white, black, white, white}
+	have := p.TextColors()
+	if len(have) != len(want) {
+		t.Fatalf("have %d colors, want %d", len(have), len(want))
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d %s: have %v, want %v", i, p[i].ToHTML(), have[i], want[i])
+		}
+	}
+}
+
+func TestCategoricalAvoiding(t *testing.T) {
+	for _, test := range []struct {
+		n          int
+		hue, width float64
+	}{
+		{8, HueRed, 1.0 / 6},
+		{5, HueGreen, 0.3},
+		{12, 0.95, 0.2},
+		{1, HueBlue, 0.5},
+	} {
+		colors := CategoricalAvoiding(test.n, test.hue, test.width)
+		if len(colors) != test.n {
+			t.Errorf("have %d colors, want %d", len(colors), test.n)
+		}
+		for _, c := range colors {
+			h := c.ToHSL().H
+			d := min(real.Diff(h, test.hue), 1-real.Diff(h, test.hue))
+			if d < test.width/2 {
+				t.Errorf("%s has hue %.3f, within %.3f of %.3f", c.ToHTML(), h, test.width/2, test.hue)
+			}
+		}
+	}
+}
+
+func TestResample(t *testing.T) {
+	p := Palette{{0.8, 0.4, 0.2}, {0.2, 0.3, 0.6}}
+	have := p.Resample(5)
+	if len(have) != 5 {
+		t.Fatalf("have %d colors, want 5", len(have))
+	}
+	if !eqRGB(have[0], p[0]) || !eqRGB(have[4], p[1]) {
+		t.Errorf("ends: have %v and %v, want %v and %v", have[0], have[4], p[0], p[1])
+	}
+	if mid := mixLab(p[0], p[1], 0.5); !eqRGB(have[2], mid) {
+		t.Errorf("middle: have %v, want %v", have[2], mid)
+	}
+	// even steps in CIELAB
+	step := have[0].ToLab().L - have[1].ToLab().L
+	for i := 1; i < len(have)-1; i++ {
+		if d := have[i].ToLab().L - have[i+1].ToLab().L; real.Diff(d, step) > 0.5 {
+			t.Errorf("step %d: L* falls by %.2f, want %.2f", i, d, step)
+		}
+	}
+
+	five := Palette{{1, 0, 0}, {1, 1, 0}, {0, 1, 0}, {0, 1, 1}, {0, 0, 1}}
+	if have := five.Resample(3); !eqRGB(have[0], five[0]) || !eqRGB(have[1], five[2]) || !eqRGB(have[2], five[4]) {
+		t.Errorf("subsample: have %v, want %v", have, Palette{five[0], five[2], five[4]})
+	}
+	if have := five.Resample(12); len(have) != 12 {
+		t.Errorf("12: have %d colors", len(have))
+	}
+}
+
+func TestChannelStats(t *testing.T) {
+	colors := []RGB{{0, 0.2, 0.5}, {1, 0.4, 0.5}, {0.5, 0.6, 0.5}, {0.5, 0.8, 0.5}}
+	if have, want := ChannelMean(colors), (RGB{0.5, 0.5, 0.5}); !eqRGB(have, want) {
+		t.Errorf("mean: have %v, want %v", have, want)
+	}
+	// variances of 0.125, 0.05, and 0
+	if have, want := ChannelStdDev(colors), (RGB{math.Sqrt(0.125), math.Sqrt(0.05), 0}); !eqRGB(have, want) {
+		t.Errorf("standard deviation: have %v, want %v", have, want)
+	}
+
+	if have := ChannelMean(nil); have != (RGB{}) {
+		t.Errorf("empty mean: have %v", have)
+	}
+	if have := ChannelStdDev([]RGB{{0.1, 0.2, 0.3}}); have != (RGB{}) {
+		t.Errorf("one color: have %v, want no spread", have)
+	}
+}
+
+func TestPaletteIndex(t *testing.T) {
+	p := Palette{black, white, {1, 0, 0}, {0, 0, 1}}
+	for _, test := range []struct {
+		in   color.Color
+		want int
+	}{
+		{RGB{0.1, 0.1, 0.1}, 0},
+		{RGB{0.9, 0.95, 0.9}, 1},
+		{RGB{0.8, 0.1, 0.2}, 2},
+		{color.RGBA{0x20, 0x10, 0xa0, 0xff}, 3},
+		{RGBA{1, 0, 0, 0.5}, 2},
+	} {
+		if have := p.Index(test.in); have != test.want {
+			t.Errorf("%v: have %d, want %d", test.in, have, test.want)
+		}
+		if have := p.Convert(test.in); have != p[test.want] {
+			t.Errorf("%v: have %v, want %v", test.in, have, p[test.want])
+		}
+	}
+	if i, c := Palette(nil).Index(white), Palette(nil).Convert(white); i != -1 || c != nil {
+		t.Errorf("empty: have %d %v, want -1 <nil>", i, c)
+	}
+
+	pal := p.ColorPalette()
+	if len(pal) != len(p) {
+		t.Fatalf("have %d colors, want %d", len(pal), len(p))
+	}
+	img := image.NewPaletted(image.Rect(0, 0, 1, 1), pal)
+	img.Set(0, 0, RGB{0.8, 0.1, 0.2})
+	if i := img.ColorIndexAt(0, 0); i != 2 {
+		t.Errorf("paletted image: have index %d, want 2", i)
+	}
+}
+
+func TestBlendPalettes(t *testing.T) {
+	light := Palette{FromHexInt(0xf5f5f5), FromHexInt(0xd0e4ff), FromHexInt(0xffd6d6)}
+	dark := Palette{FromHexInt(0x1e1e1e), FromHexInt(0x102a4c), FromHexInt(0x4c1010)}
+
+	mid, err := BlendPalettes(light, dark, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mid) != len(light) {
+		t.Fatalf("have %d colors, want %d", len(mid), len(light))
+	}
+	for i, c := range mid {
+		l, ll, ld := c.ToLab().L, light[i].ToLab().L, dark[i].ToLab().L
+		if want := (ll + ld) / 2; real.Diff(l, want) > 0.01 {
+			t.Errorf("%d: have lightness %.3f, want %.3f", i, l, want)
+		}
+		if !(ld < l && l < ll) {
+			t.Errorf("%d: lightness %.3f is not between %.3f and %.3f", i, l, ld, ll)
+		}
+	}
+
+	for tt, want := range map[float64]Palette{-1: light, 0: light, 1: dark, 2: dark} {
+		have, err := BlendPalettes(light, dark, tt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				t.Errorf("t=%v, %d: have %v, want %v", tt, i, have[i], want[i])
+			}
+		}
+	}
+
+	if p, err := BlendPalettes(light, dark[:2], 0.5); err == nil {
+		t.Errorf("mismatched lengths: have %v, want an error", p)
+	}
+}
@@ -0,0 +1,137 @@
+package color
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+// RGBA is an RGB color with an alpha channel.
+// The color channels are not premultiplied by alpha.
+type RGBA struct {
+	R, G, B, A float64 // Red, Green, Blue, Alpha values in [0, 1]
+}
+
+// RGBA implements color.Color, returning alpha-premultiplied values as the interface requires
+func (c RGBA) RGBA() (r, g, b, a uint32) {
+	r = uint32(real.MapVal(c.R*c.A, 0, 1, 0, 0xffff))
+	g = uint32(real.MapVal(c.G*c.A, 0, 1, 0, 0xffff))
+	b = uint32(real.MapVal(c.B*c.A, 0, 1, 0, 0xffff))
+	a = uint32(real.MapVal(c.A, 0, 1, 0, 0xffff))
+	return
+}
+
+// HTMLToRGBA takes a string like '#11223380' or '11223380', with an alpha
+// byte after the color, or the CSS shorthand '#1238' for it, and returns an
+// RGBA. Strings HTMLToRGB accepts are read as opaque colors.
+// Surrounding whitespace is ignored.
+func HTMLToRGBA(in string) (RGBA, error) {
+	in = strings.TrimSpace(in)
+	hex := strings.TrimPrefix(in, "#")
+	if len(hex) != 4 && len(hex) != 8 {
+		c, err := HTMLToRGB(in)
+		if err != nil {
+			return RGBA{}, err
+		}
+		return c.WithAlpha(1), nil
+	}
+	if !isHex(hex) {
+		return RGBA{}, fmt.Errorf("Invalid hex color %q", in)
+	}
+
+	if len(hex) == 4 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2], hex[3], hex[3]})
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("Invalid hex color %q", in)
+	}
+	return FromHexInt(uint32(v >> 8)).WithAlpha(float64(v&0xff) / 0xff), nil
+}
+
+// ToHTML returns c as 8 hex digits, the color's 6 followed by the alpha byte
+func (c RGBA) ToHTML() string {
+	return fmt.Sprintf("%s%02x", c.ToRGB().ToHTML(), channel8(c.A))
+}
+
+// ToRGB returns the color channels of c, dropping alpha
+func (c RGBA) ToRGB() RGB {
+	return RGB{c.R, c.G, c.B}
+}
+
+// RGBAModel converts colors to RGBA, keeping their alpha.
+// Fully transparent colors become the zero RGBA.
+var RGBAModel color.Model = color.ModelFunc(rgbaModel)
+
+func rgbaModel(c color.Color) color.Color {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return RGBA{}
+	}
+	// undo the premultiplication the color.Color interface requires
+	return RGBA{
+		float64(r) / float64(a),
+		float64(g) / float64(a),
+		float64(b) / float64(a),
+		real.MapVal(float64(a), 0, 0xffff, 0, 1),
+	}
+}
+
+// Over composites c over an opaque background, returning the color that results
+func (c RGBA) Over(bg RGB) RGB {
+	return Mix(bg, c.ToRGB(), c.A)
+}
+
+// EffectiveLuminance returns the WCAG relative luminance c shows with when
+// composited over bg, for checking the contrast of translucent text
+func (c RGBA) EffectiveLuminance(bg RGB) float64 {
+	return c.Over(bg).Luminance()
+}
+
+// ToHTMLOver composites c over bg and returns the 6 digit hex of the result,
+// for contexts that can't express transparency
+func (c RGBA) ToHTMLOver(bg RGB) string {
+	return c.Over(bg).ToHTML()
+}
+
+// FadeOut returns n copies of c whose alpha steps evenly from opaque down to
+// fully transparent, for fade out animations. A single step is opaque.
+func (c RGB) FadeOut(n int) []RGBA {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]RGBA, n)
+	for i := range out {
+		a := 1.0
+		if n > 1 {
+			a = 1 - float64(i)/float64(n-1)
+		}
+		out[i] = RGBA{c.R, c.G, c.B, a}
+	}
+	return out
+}
+
+// How a glow differs from its base color
+const (
+	glowLift  = 0.4  // share of the remaining headroom to white that lightness rises by
+	glowSat   = 0.85 // factor saturation is scaled by
+	glowAlpha = 0.6
+)
+
+// GlowColor returns a halo color for neon effects built around c: lighter,
+// a touch less saturated, and translucent, to be blurred behind the base color
+func (c RGB) GlowColor() RGBA {
+	hsl := c.ToHSL()
+	hsl.L += (1 - hsl.L) * glowLift
+	hsl.S *= glowSat
+	return hsl.ToRGB().WithAlpha(glowAlpha)
+}
+
+// WithAlpha returns c with the given alpha
+func (c RGB) WithAlpha(a float64) RGBA {
+	return RGBA{c.R, c.G, c.B, a}
+}
@@ -0,0 +1,166 @@
+package color
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+var _ color.Color = RGBA{}
+
+func TestToHTMLOver(t *testing.T) {
+	for _, test := range []struct {
+		c    RGBA
+		bg   RGB
+		want string
+	}{
+		{RGBA{1, 0, 0, 0.5}, white, RGB{1, 0.5, 0.5}.ToHTML()},
+		{RGBA{1, 0, 0, 1}, white, "ff0000"},
+		{RGBA{1, 0, 0, 0}, white, "ffffff"},
+		{RGBA{0, 0, 1, 0.5}, black, RGB{0, 0, 0.5}.ToHTML()},
+	} {
+		if have := test.c.ToHTMLOver(test.bg); have != test.want {
+			t.Errorf("%v over %v: have %s, want %s", test.c, test.bg, have, test.want)
+		}
+	}
+}
+
+func TestFadeOut(t *testing.T) {
+	c := RGB{0.2, 0.4, 0.6}
+	for _, n := range []int{2, 3, 10} {
+		steps := c.FadeOut(n)
+		if len(steps) != n {
+			t.Fatalf("%2d: have %d steps", n, len(steps))
+		}
+		if steps[0].A != 1 || steps[n-1].A != 0 {
+			t.Errorf("%2d: runs from %f to %f, want 1 to 0", n, steps[0].A, steps[n-1].A)
+		}
+		for i, s := range steps {
+			if (RGB{s.R, s.G, s.B}) != c {
+				t.Errorf("%2d: step %d has color %v, want %v", n, i, s, c)
+			}
+			if i > 0 && s.A >= steps[i-1].A {
+				t.Errorf("%2d: alpha rises from %f to %f at step %d", n, steps[i-1].A, s.A, i)
+			}
+		}
+	}
+	if steps := c.FadeOut(1); len(steps) != 1 || steps[0].A != 1 {
+		t.Errorf("1: have %v, want one opaque step", steps)
+	}
+}
+
+func TestGlowColor(t *testing.T) {
+	for _, c := range []RGB{{1, 0, 0.5}, {0, 0.8, 1}, {0.1, 0.1, 0.1}} {
+		glow := c.GlowColor()
+		base, halo := c.ToHSL(), RGB{glow.R, glow.G, glow.B}.ToHSL()
+		if halo.L <= base.L {
+			t.Errorf("%s: glow lightness %f isn't above %f", c.ToHTML(), halo.L, base.L)
+		}
+		if halo.S > base.S+epsilonF {
+			t.Errorf("%s: glow saturation %f is above %f", c.ToHTML(), halo.S, base.S)
+		}
+		if glow.A >= 1 || glow.A <= 0 {
+			t.Errorf("%s: glow alpha %f isn't translucent", c.ToHTML(), glow.A)
+		}
+	}
+}
+
+// eqColor reports whether l and r have the same 16 bit RGBA values, give or take one
+func eqColor(l, r color.Color) bool {
+	lr, lg, lb, la := l.RGBA()
+	rr, rg, rb, ra := r.RGBA()
+	near := func(a, b uint32) bool { return max(a, b)-min(a, b) <= 1 }
+	return near(lr, rr) && near(lg, rg) && near(lb, rb) && near(la, ra)
+}
+
+func TestRGBAModel(t *testing.T) {
+	for _, test := range []struct {
+		in   color.Color
+		want RGBA
+	}{
+		{color.NRGBA{0xff, 0x80, 0, 0x80}, RGBA{1, 0x80 / 255.0, 0, 0x80 / 255.0}},
+		{color.RGBA{0x40, 0, 0x40, 0x80}, RGBA{0x40 / 128.0, 0, 0x40 / 128.0, 0x80 / 255.0}},
+		{color.Opaque, RGBA{1, 1, 1, 1}},
+		{color.Transparent, RGBA{}},
+		{RGB{0.2, 0.4, 0.6}, RGBA{0.2, 0.4, 0.6, 1}},
+		{RGBA{0.2, 0.4, 0.6, 0.5}, RGBA{0.2, 0.4, 0.6, 0.5}},
+	} {
+		have := RGBAModel.Convert(test.in).(RGBA)
+		const eps = 1e-3 // 16 bit precision, further loosened by unpremultiplying
+		if real.Diff(have.R, test.want.R) > eps || real.Diff(have.G, test.want.G) > eps ||
+			real.Diff(have.B, test.want.B) > eps || real.Diff(have.A, test.want.A) > eps {
+			t.Errorf("%v: have %v, want %v", test.in, have, test.want)
+		}
+	}
+}
+
+func TestEffectiveLuminance(t *testing.T) {
+	text := RGB{0.1, 0.1, 0.1}
+	for _, bg := range []RGB{white, black, {0.2, 0.6, 0.9}} {
+		if have, want := text.WithAlpha(1).EffectiveLuminance(bg), text.Luminance(); real.Diff(have, want) > epsilonF {
+			t.Errorf("%s opaque: have %f, want %f", bg.ToHTML(), have, want)
+		}
+		if have, want := text.WithAlpha(0).EffectiveLuminance(bg), bg.Luminance(); real.Diff(have, want) > epsilonF {
+			t.Errorf("%s clear: have %f, want %f", bg.ToHTML(), have, want)
+		}
+		prev := text.Luminance()
+		for _, a := range []float64{0.8, 0.5, 0.2} {
+			l := text.WithAlpha(a).EffectiveLuminance(bg)
+			if real.Diff(l, bg.Luminance()) >= real.Diff(prev, bg.Luminance()) {
+				t.Errorf("%s alpha %.1f: %f isn't closer to the background's %f than %f", bg.ToHTML(), a, l, bg.Luminance(), prev)
+			}
+			prev = l
+		}
+	}
+}
+
+func TestHTMLToRGBA(t *testing.T) {
+	const hex = "11223380"
+	c, err := HTMLToRGBA("#" + hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RGBA{0x11 / 255.0, 0x22 / 255.0, 0x33 / 255.0, 0x80 / 255.0}); c != want {
+		t.Errorf("have %v, want %v", c, want)
+	}
+	if have := c.ToHTML(); have != hex {
+		t.Errorf("back: have %s, want %s", have, hex)
+	}
+
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"11223380", "11223380"},
+		{" #112233ff ", "112233ff"},
+		{"#1238", "11223388"},
+		{"#123", "112233ff"},
+		{"112233", "112233ff"},
+		{"#00000000", "00000000"},
+	} {
+		if have, err := HTMLToRGBA(test.in); err != nil || have.ToHTML() != test.want {
+			t.Errorf("%q: have %s %v, want %s", test.in, have.ToHTML(), err, test.want)
+		}
+	}
+	for _, in := range []string{"", "#12", "#12345", "#1122334", "#112233zz", "#zz223380", "#112233800", "red!", "#red!"} {
+		if have, err := HTMLToRGBA(in); err == nil {
+			t.Errorf("%q: have %v, want an error", in, have)
+		}
+	}
+	// errors name what the caller wrote, not its expansion
+	for _, in := range []string{"red!", "#112233zz", "#1g3"} {
+		if _, err := HTMLToRGBA(in); err == nil || !strings.Contains(err.Error(), strconv.Quote(in)) {
+			t.Errorf("%q: have %v, want an error naming it", in, err)
+		}
+	}
+
+	// Parse keeps 6 digits opaque and gives 8 an alpha
+	if have, err := Parse("#112233"); err != nil || have != (RGB{0x11 / 255.0, 0x22 / 255.0, 0x33 / 255.0}) {
+		t.Errorf("Parse 6 digits: have %v %v", have, err)
+	}
+	if have, err := Parse("#11223380"); err != nil || have != c {
+		t.Errorf("Parse 8 digits: have %v %v, want %v", have, err, c)
+	}
+}
@@ -0,0 +1,107 @@
+package color
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Colors whose chroma falls below this are treated as gray when reasoning about hue
+const grayThreshold = 0.02
+
+// StableHue returns the hue of c along with whether it is meaningful.
+// Near grays have a hue that swings wildly with tiny changes in their channels,
+// so ok is false when the chroma (the spread between the largest and smallest
+// channels) is below a small threshold, and callers should ignore the hue.
+func (c RGB) StableHue() (hue float64, ok bool) {
+	if max(c.R, c.G, c.B)-min(c.R, c.G, c.B) < grayThreshold {
+		return 0, false
+	}
+	return c.ToHSL().H, true
+}
+
+// SortByHue sorts colors in place by hue.
+// Colors without a stable hue are grouped at the front, ordered from dark to light,
+// so that near grays stay together instead of being scattered around the wheel.
+func SortByHue(colors []RGB) {
+	slices.SortStableFunc(colors, func(a, b RGB) int {
+		ha, oka := a.StableHue()
+		hb, okb := b.StableHue()
+		switch {
+		case !oka && okb:
+			return -1
+		case oka && !okb:
+			return 1
+		case oka && okb && ha != hb:
+			return cmp.Compare(ha, hb)
+		}
+		return cmp.Compare(a.ToHSL().L, b.ToHSL().L)
+	})
+}
+
+// RankByLuminance returns, for each color, its 0 based rank from darkest to lightest
+// by relative luminance. Colors of equal luminance are ranked in the order given.
+func RankByLuminance(colors []RGB) []int {
+	order := make([]int, len(colors))
+	lums := make([]float64, len(colors))
+	for i, c := range colors {
+		order[i] = i
+		lums[i] = c.Luminance()
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(lums[a], lums[b])
+	})
+
+	ranks := make([]int, len(colors))
+	for rank, i := range order {
+		ranks[i] = rank
+	}
+	return ranks
+}
+
+// Vividness scores how lively c looks, in [0, 1], as its HSL saturation weighted
+// by how close its lightness is to the middle: S × (1 - |2L - 1|).
+// Fully saturated mid tones score 1; grays, black, and white score 0.
+func (c RGB) Vividness() float64 {
+	hsl := c.ToHSL()
+	return hsl.S * (1 - math.Abs(2*hsl.L-1))
+}
+
+// SortByVividness sorts colors in place from most to least vivid
+func SortByVividness(colors []RGB) {
+	slices.SortStableFunc(colors, func(a, b RGB) int {
+		return cmp.Compare(b.Vividness(), a.Vividness())
+	})
+}
+
+// Energy scores the perceived energy of c, in [0, 1], as the square root of
+// its relative luminance times its HSL saturation: √Y × S. Unlike Vividness it
+// favors bright colors, so a saturated yellow outscores a saturated blue.
+// Black and every gray score 0.
+func (c RGB) Energy() float64 {
+	return math.Sqrt(c.Luminance()) * c.ToHSL().S
+}
+
+// Weights of the parts of Approachability
+const (
+	approachWarmth     = 0.4
+	approachBrightness = 0.4
+	approachModeration = 0.2
+)
+
+// Approachability scores how friendly c feels, in [0, 1], as a weighted mean
+// of three parts:
+//   - warmth, 0.4: (1 + cos(2π(H - 1/12))) / 2, peaking at orange and bottoming
+//     out at azure, pulled toward 1/2 for grays in proportion to HSL saturation
+//   - brightness, 0.4: HSL lightness
+//   - moderation, 0.2: 1 - |2S - 1| for HSV saturation S, peaking at half saturation
+//
+// Warm, light, moderately saturated colors such as peach score highest and
+// dark, cold ones lowest.
+func (c RGB) Approachability() float64 {
+	hsl := c.ToHSL()
+	warmth := (1 + math.Cos(2*math.Pi*(hsl.H-1/12.0))) / 2
+	warmth = 0.5 + (warmth-0.5)*hsl.S
+	moderation := 1 - math.Abs(2*c.Saturation(HSVSat)-1)
+	return approachWarmth*warmth + approachBrightness*hsl.L + approachModeration*moderation
+}
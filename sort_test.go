@@ -0,0 +1,133 @@
+package color
+
+import "testing"
+
+func TestStableHue(t *testing.T) {
+	for _, c := range []RGB{{0.5, 0.5, 0.5}, {0.5, 0.505, 0.5}, {0.01, 0, 0}, {0.99, 1, 1}} {
+		if h, ok := c.StableHue(); ok {
+			t.Errorf("%v: have hue %f, want none", c, h)
+		}
+	}
+	if h, ok := (RGB{1, 0, 0}).StableHue(); !ok || h != 0 {
+		t.Errorf("red: have %f %v, want 0 true", h, ok)
+	}
+}
+
+func TestSortByHue(t *testing.T) {
+	colors := []RGB{
+		{0, 0, 1},          // blue
+		{0.7, 0.7, 0.705},  // light bluish gray
+		{1, 0, 0},          // red
+		{0.3, 0.305, 0.3},  // dark greenish gray
+		{0, 1, 0},          // green
+		{0.5, 0.5, 0.5},    // gray
+		{0.505, 0.5, 0.5},  // reddish gray
+		{0.51, 0.5, 0.505}, // another reddish gray
+	}
+	want := []RGB{
+		{0.3, 0.305, 0.3},
+		{0.5, 0.5, 0.5},
+		{0.505, 0.5, 0.5},
+		{0.51, 0.5, 0.505},
+		{0.7, 0.7, 0.705},
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+	SortByHue(colors)
+	for i := range want {
+		if colors[i] != want[i] {
+			t.Errorf("%d: have %v, want %v", i, colors[i], want[i])
+		}
+	}
+}
+
+func TestRankByLuminance(t *testing.T) {
+	colors := []RGB{
+		{0.5, 0.5, 0.5}, // mid gray
+		{0, 0, 0},       // black
+		{1, 1, 1},       // white
+		{0, 0, 1},       // blue, darker than mid gray
+		{0.5, 0.5, 0.5}, // mid gray again
+		{1, 1, 0},       // yellow
+	}
+	want := []int{2, 0, 5, 1, 3, 4}
+	have := RankByLuminance(colors)
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("%d %s: have rank %d, want %d", i, colors[i].ToHTML(), have[i], want[i])
+		}
+	}
+	if have := RankByLuminance(nil); len(have) != 0 {
+		t.Errorf("nil: have %v", have)
+	}
+}
+
+func TestVividness(t *testing.T) {
+	vivid := HSL{0.6, 1, 0.5}.ToRGB()
+	pale := HSL{0.6, 1, 0.9}.ToRGB()
+	dark := HSL{0.6, 1, 0.1}.ToRGB()
+	dull := HSL{0.6, 0.3, 0.5}.ToRGB()
+	gray := RGB{0.5, 0.5, 0.5}
+
+	for _, c := range []RGB{pale, dark, dull, gray} {
+		if vivid.Vividness() <= c.Vividness() {
+			t.Errorf("%s scores %f, not below %f", c.ToHTML(), c.Vividness(), vivid.Vividness())
+		}
+	}
+	if v := gray.Vividness(); v != 0 {
+		t.Errorf("gray: have %f, want 0", v)
+	}
+
+	colors := []RGB{gray, pale, vivid, dull}
+	SortByVividness(colors)
+	want := []RGB{vivid, dull, pale, gray}
+	for i := range want {
+		if colors[i] != want[i] {
+			t.Errorf("%d: have %s, want %s", i, colors[i].ToHTML(), want[i].ToHTML())
+		}
+	}
+}
+
+func TestEnergy(t *testing.T) {
+	for _, c := range []RGB{black, white, {0.5, 0.5, 0.5}, {0.1, 0.1, 0.1}} {
+		if e := c.Energy(); e != 0 {
+			t.Errorf("%s: have %f, want 0", c.ToHTML(), e)
+		}
+	}
+	yellow := RGB{1, 1, 0}
+	if e := yellow.Energy(); e < 0.9 {
+		t.Errorf("yellow: have %f, want high", e)
+	}
+	for _, c := range []RGB{{0, 0, 1}, {0.5, 0.45, 0.4}, HSL{0.15, 1, 0.2}.ToRGB()} {
+		if c.Energy() >= yellow.Energy() {
+			t.Errorf("%s scores %f, not below yellow's %f", c.ToHTML(), c.Energy(), yellow.Energy())
+		}
+	}
+}
+
+func TestApproachability(t *testing.T) {
+	peach, navy := RGB{1, 0.8, 0.65}, RGB{0.05, 0.1, 0.3}
+	if p, n := peach.Approachability(), navy.Approachability(); p <= n {
+		t.Errorf("warm pastel scores %f, not above dark cold %f", p, n)
+	}
+	for _, c := range []RGB{peach, navy, black, white, {1, 0, 0}, {0, 1, 1}} {
+		if a := c.Approachability(); a < 0 || a > 1 {
+			t.Errorf("%s: have %f, out of [0, 1]", c.ToHTML(), a)
+		}
+	}
+
+	// warmer and lighter both score higher
+	base := HSL{0.6, 0.5, 0.4}
+	for _, test := range []struct {
+		name string
+		c    HSL
+	}{
+		{"warmer", HSL{0.08, 0.5, 0.4}},
+		{"lighter", HSL{0.6, 0.5, 0.6}},
+	} {
+		if have, than := test.c.ToRGB().Approachability(), base.ToRGB().Approachability(); have <= than {
+			t.Errorf("%s: scores %f, not above %f", test.name, have, than)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package color
+
+import (
+	"image"
+	"math"
+)
+
+// Hues of the 12 colors of the artist's red-yellow-blue wheel, as HSL hues
+// in [0, 1). The artist's wheel gives as much room to red through yellow as
+// to yellow through blue, so the hues are not evenly spaced in HSL, where
+// red through yellow spans only a sixth of the circle.
+const (
+	HueRed          = 0.0
+	HueRedOrange    = 15.0 / 360
+	HueOrange       = 30.0 / 360
+	HueYellowOrange = 45.0 / 360
+	HueYellow       = 60.0 / 360
+	HueYellowGreen  = 90.0 / 360
+	HueGreen        = 120.0 / 360
+	HueBlueGreen    = 180.0 / 360
+	HueBlue         = 240.0 / 360
+	HueBlueViolet   = 260.0 / 360
+	HueViolet       = 280.0 / 360
+	HueRedViolet    = 320.0 / 360
+)
+
+// WheelHues returns the 12 artist's wheel hues in order, starting at red
+func WheelHues() []float64 {
+	return []float64{
+		HueRed, HueRedOrange, HueOrange, HueYellowOrange,
+		HueYellow, HueYellowGreen, HueGreen, HueBlueGreen,
+		HueBlue, HueBlueViolet, HueViolet, HueRedViolet,
+	}
+}
+
+// Cartesian places c in the HSL cylinder: hue and saturation are taken as polar
+// coordinates on the unit disk, giving x and y, and lightness becomes the height z
+func (c HSL) Cartesian() (x, y, z float64) {
+	theta := 2 * math.Pi * c.H
+	return c.S * math.Cos(theta), c.S * math.Sin(theta), c.L
+}
+
+// ColorWheel renders a 2r×2r HSL color wheel at the given lightness.
+// Hue runs counterclockwise from red at three o'clock and saturation grows
+// from gray at the center to full at the rim. Pixels outside the disk are transparent.
+func ColorWheel(radius int, lightness float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2*radius, 2*radius))
+	r := float64(radius)
+	for y := range 2 * radius {
+		for x := range 2 * radius {
+			dx, dy := float64(x)+0.5-r, r-float64(y)-0.5
+			s := math.Hypot(dx, dy) / r
+			if s > 1 {
+				continue
+			}
+			h := wrapHue(math.Atan2(dy, dx) / (2 * math.Pi))
+			img.Set(x, y, HSL{h, s, lightness})
+		}
+	}
+	return img
+}
@@ -0,0 +1,91 @@
+package color
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kendfss/oprs/math/real"
+)
+
+func TestWheelHues(t *testing.T) {
+	hues := WheelHues()
+	if len(hues) != 12 {
+		t.Fatalf("have %d hues, want 12", len(hues))
+	}
+	for i := 1; i < len(hues); i++ {
+		if hues[i] <= hues[i-1] || hues[i] >= 1 {
+			t.Errorf("%2d: have %f after %f, want increasing hues in [0, 1)", i, hues[i], hues[i-1])
+		}
+	}
+
+	// each name renders as the color it names
+	for _, test := range []struct {
+		name string
+		hue  float64
+		want RGB
+	}{
+		{"red", HueRed, RGB{1, 0, 0}},
+		{"orange", HueOrange, RGB{1, 0.5, 0}},
+		{"yellow", HueYellow, RGB{1, 1, 0}},
+		{"green", HueGreen, RGB{0, 1, 0}},
+		{"blue-green", HueBlueGreen, RGB{0, 1, 1}},
+		{"blue", HueBlue, RGB{0, 0, 1}},
+	} {
+		if have := (HSL{test.hue, 1, 0.5}).ToRGB(); !eqRGB(have, test.want) {
+			t.Errorf("%s: have %v, want %v", test.name, have, test.want)
+		}
+	}
+	// violets sit between blue and magenta, short of either
+	for _, h := range []float64{HueBlueViolet, HueViolet} {
+		if c := (HSL{h, 1, 0.5}).ToRGB(); !(c.B == 1 && c.G == 0 && c.R > 0 && c.R < 1) {
+			t.Errorf("%f: have %v, want a violet", h, c)
+		}
+	}
+}
+
+func TestCartesian(t *testing.T) {
+	for _, test := range []struct {
+		c       HSL
+		x, y, z float64
+	}{
+		{HSL{HueRed, 1, 0.5}, 1, 0, 0.5},
+		{HSL{0.25, 0.5, 0.2}, 0, 0.5, 0.2},
+		{HSL{0.5, 0.8, 1}, -0.8, 0, 1},
+		{HSL{0.75, 1, 0}, 0, -1, 0},
+		{HSL{0.3, 0, 0.7}, 0, 0, 0.7},
+	} {
+		x, y, z := test.c.Cartesian()
+		if real.Diff(x, test.x) > epsilonF || real.Diff(y, test.y) > epsilonF || real.Diff(z, test.z) > epsilonF {
+			t.Errorf("%v: have (%f, %f, %f), want (%f, %f, %f)", test.c, x, y, z, test.x, test.y, test.z)
+		}
+	}
+}
+
+func TestColorWheel(t *testing.T) {
+	const radius = 50
+	img := ColorWheel(radius, 0.5)
+	if have, want := img.Bounds().Size(), image.Pt(2*radius, 2*radius); have != want {
+		t.Fatalf("size: have %v, want %v", have, want)
+	}
+
+	if c := at(img, radius, radius).ToHSL(); c.S > 0.05 {
+		t.Errorf("center: have %v, want gray", c)
+	}
+	for _, test := range []struct {
+		x, y int
+		hue  float64
+	}{
+		{2*radius - 1, radius, HueRed},
+		{radius, 0, 0.25},
+		{0, radius, 0.5},
+		{radius, 2*radius - 1, 0.75},
+	} {
+		c := at(img, test.x, test.y).ToHSL()
+		if c.S < 0.9 || hueDistance(c.H, test.hue) > 0.02 {
+			t.Errorf("(%d, %d): have %v, want saturated hue %.2f", test.x, test.y, c, test.hue)
+		}
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("corner: have alpha %d, want 0", a)
+	}
+}